@@ -1,11 +1,15 @@
 package build
 
 import (
+	"fmt"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/gravitational/gamma/internal/git"
 	"github.com/gravitational/gamma/internal/logger"
+	"github.com/gravitational/gamma/internal/report"
+	"github.com/gravitational/gamma/internal/size"
 	"github.com/gravitational/gamma/internal/utils"
 	"github.com/gravitational/gamma/internal/workspace"
 	"github.com/jedib0t/go-pretty/v6/text"
@@ -14,6 +18,12 @@ import (
 
 var outputDirectory string
 var workingDirectory string
+var keepDist bool
+var header bool
+var sizeBaseline string
+var sizeOutput string
+var reportFormat string
+var reportFile string
 
 var Command = &cobra.Command{
 	Use:   "build",
@@ -22,6 +32,20 @@ var Command = &cobra.Command{
 	Run: func(_ *cobra.Command, _ []string) {
 		started := time.Now()
 
+		var format report.Format
+		if reportFormat != "" {
+			f, err := report.ParseFormat(reportFormat)
+			if err != nil {
+				logger.Fatal(err)
+			}
+
+			if reportFile == "" {
+				logger.Fatal("--report-file is required when --report is set")
+			}
+
+			format = f
+		}
+
 		if workingDirectory == "the current working directory" { // this is the default value from the flag
 			wd, err := os.Getwd()
 			if err != nil {
@@ -44,7 +68,16 @@ var Command = &cobra.Command{
 			logger.Fatalf("could not create output directory: %v", err)
 		}
 
-		ws := workspace.New(wd, od)
+		commit, err := git.HeadCommit(wd)
+		if err != nil && header {
+			logger.Warningf("could not get the current commit: %v", err)
+		}
+
+		ws := workspace.New(wd, od, workspace.Options{
+			KeepDist: keepDist,
+			Header:   header,
+			Commit:   commit,
+		})
 
 		logger.Info("collecting actions")
 
@@ -64,23 +97,85 @@ var Command = &cobra.Command{
 
 		logger.Infof("found actions [%s]", strings.Join(actionNames, ", "))
 
+		var baseline size.Baseline
+		if sizeBaseline != "" {
+			b, err := size.ReadBaseline(sizeBaseline)
+			if err != nil {
+				logger.Fatal(err)
+			}
+
+			baseline = b
+		}
+
+		sizes := make(size.Baseline)
+
 		var hasError bool
 
+		r := report.New("build")
+
 		for _, action := range actions {
 			logger.Infof("action %s has changes, building", action.Name())
 
-			buildStarted := time.Now()
+			result := r.Add(&report.ActionResult{
+				Name:       action.Name(),
+				Repository: fmt.Sprintf("%s/%s", action.Owner(), action.Name()),
+				Status:     report.StatusSuccess,
+			})
 
-			if err := action.Build(); err != nil {
+			warnings, err := action.CheckRuntime()
+			for _, warning := range warnings {
+				logger.Warningf("action %s: %s", action.Name(), warning)
+			}
+
+			if err != nil {
 				hasError = true
-				logger.Errorf("error building action %s: %v", action.Name(), err)
+				result.Fail(err)
+				logger.Errorf("error checking the runtime of action %s: %v", action.Name(), err)
 
 				continue
 			}
 
+			buildStarted := time.Now()
+
+			err = action.Build()
+
 			buildTook := time.Since(buildStarted)
+			result.BuildDuration = buildTook.Seconds()
+
+			if err != nil {
+				hasError = true
+				result.Fail(err)
+				logger.Errorf("error building action %s: %v", action.Name(), err)
+
+				continue
+			}
 
 			logger.Successf("successfully built action %s in %.2fs", action.Name(), buildTook.Seconds())
+
+			current, err := size.Check(action.Name(), action.OutputDirectory(), action.Config().SizeBudget, baseline[action.Name()])
+			if current != nil {
+				sizes[action.Name()] = current
+			}
+
+			if err != nil {
+				hasError = true
+				result.Fail(err)
+				logger.Errorf("action %s: %v", action.Name(), err)
+			}
+		}
+
+		if sizeOutput != "" {
+			if err := sizes.Write(sizeOutput); err != nil {
+				logger.Error(err)
+			}
+		}
+
+		r.Finish()
+
+		if format != "" {
+			if err := r.Write(format, reportFile); err != nil {
+				logger.Error(err)
+			}
 		}
 
 		bold := text.Colors{text.FgWhite, text.Bold}
@@ -98,4 +193,10 @@ var Command = &cobra.Command{
 func init() {
 	Command.Flags().StringVarP(&outputDirectory, "output", "o", "build", "output directory")
 	Command.Flags().StringVarP(&workingDirectory, "directory", "d", "the current working directory", "directory containing the monorepo of actions")
+	Command.Flags().BoolVar(&header, "header", false, "add a comment to each action.yml naming the file and commit it was generated from")
+	Command.Flags().BoolVar(&keepDist, "keep-dist", false, "copy each action's dist into the output directory instead of moving it")
+	Command.Flags().StringVar(&sizeBaseline, "size-baseline", "", "JSON file of previous output sizes to compare against")
+	Command.Flags().StringVar(&sizeOutput, "size-output", "", "write the output sizes of each action to a JSON file, for use as a baseline")
+	Command.Flags().StringVar(&reportFormat, "report", "", "write a machine-readable report (json or junit)")
+	Command.Flags().StringVar(&reportFile, "report-file", "", "file to write the report to")
 }
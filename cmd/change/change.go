@@ -0,0 +1,84 @@
+package change
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gravitational/gamma/internal/logger"
+	"github.com/gravitational/gamma/internal/release"
+	"github.com/gravitational/gamma/internal/utils"
+	"github.com/gravitational/gamma/internal/version"
+	"github.com/gravitational/gamma/internal/workspace"
+)
+
+var workingDirectory string
+var summary string
+
+var Command = &cobra.Command{
+	Use:   "change <action> <major|minor|patch>",
+	Short: "Records a change to an action for its next release",
+	Long:  `Writes an intent file to .gamma/changes with the action, how to bump its version and a summary for its changelog. Commit it with the change, and run gamma version to release it.`,
+	Args:  cobra.ExactArgs(2),
+	Run: func(_ *cobra.Command, args []string) {
+		bump, err := version.ParseBump(args[1])
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		if workingDirectory == "the current working directory" { // this is the default value from the flag
+			wd, err := os.Getwd()
+			if err != nil {
+				logger.Fatalf("could not get current working directory: %v", err)
+			}
+
+			workingDirectory = wd
+		}
+
+		wd, _, err := utils.NormalizeDirectories(workingDirectory, "")
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		ws := workspace.New(wd, "", workspace.Options{})
+
+		actions, err := ws.CollectActions()
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		var found bool
+		for _, action := range actions {
+			if action.Name() == args[0] {
+				found = true
+			}
+		}
+
+		if !found {
+			logger.Fatalf("could not find an action named %s", args[0])
+		}
+
+		filename, err := release.WriteIntent(wd, &release.Intent{
+			Action:  args[0],
+			Bump:    bump,
+			Summary: summary,
+		})
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		if rel, err := filepath.Rel(wd, filename); err == nil {
+			filename = rel
+		}
+
+		logger.Successf("recorded a %s change to %s in %s", bump, args[0], filename)
+	},
+}
+
+func init() {
+	Command.Flags().StringVarP(&workingDirectory, "directory", "d", "the current working directory", "directory containing the monorepo of actions")
+	Command.Flags().StringVarP(&summary, "message", "m", "", "summary of the change, added to the action's changelog")
+
+	_ = Command.MarkFlagRequired("message")
+}
@@ -0,0 +1,102 @@
+package codegen
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/gravitational/gamma/internal/git"
+	"github.com/gravitational/gamma/internal/logger"
+	"github.com/gravitational/gamma/internal/utils"
+	"github.com/gravitational/gamma/internal/workspace"
+)
+
+var workingDirectory string
+
+var Command = &cobra.Command{
+	Use:   "codegen [action...]",
+	Short: "Generates typed inputs and outputs for the actions",
+	Long:  `Generates inputs.ts and outputs.ts for every Javascript action in the monorepo, or only the named ones, from its resolved action.yml.`,
+	Run: func(_ *cobra.Command, args []string) {
+		if workingDirectory == "the current working directory" { // this is the default value from the flag
+			wd, err := os.Getwd()
+			if err != nil {
+				logger.Fatalf("could not get current working directory: %v", err)
+			}
+
+			workingDirectory = wd
+		}
+
+		wd, _, err := utils.NormalizeDirectories(workingDirectory, "")
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		// the commit is only needed by actions that use ${{ gamma.commit }}, which report it missing themselves
+		commit, _ := git.HeadCommit(wd)
+
+		ws := workspace.New(wd, "", workspace.Options{
+			Commit: commit,
+		})
+
+		actions, err := ws.CollectActions()
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		found := make(map[string]bool)
+
+		var hasError bool
+
+		for _, action := range actions {
+			if len(args) > 0 && !utils.Contains(args, action.Name()) {
+				continue
+			}
+
+			found[action.Name()] = true
+
+			files, err := action.Codegen()
+			if err != nil {
+				hasError = true
+				logger.Errorf("action %s: %v", action.Name(), err)
+
+				continue
+			}
+
+			if len(files) == 0 {
+				logger.Infof("skipping action %s, as it is not a Javascript action", action.Name())
+
+				continue
+			}
+
+			var names []string
+			for _, file := range files {
+				if rel, err := filepath.Rel(wd, file); err == nil {
+					file = rel
+				}
+
+				names = append(names, file)
+			}
+
+			logger.Successf("generated [%s] for action %s", strings.Join(names, ", "), action.Name())
+		}
+
+		for _, name := range args {
+			if !found[name] {
+				hasError = true
+				logger.Errorf("could not find an action named %s", name)
+			}
+		}
+
+		if hasError {
+			logger.Fatal(text.Colors{text.FgWhite, text.Bold}.Sprint("code generation failed"))
+		}
+	},
+}
+
+func init() {
+	Command.Flags().StringVarP(&workingDirectory, "directory", "d", "the current working directory", "directory containing the monorepo of actions")
+}
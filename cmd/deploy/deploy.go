@@ -1,14 +1,21 @@
 package deploy
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/gravitational/gamma/internal/action"
+	"github.com/gravitational/gamma/internal/config"
 	"github.com/gravitational/gamma/internal/git"
 	"github.com/gravitational/gamma/internal/logger"
+	"github.com/gravitational/gamma/internal/report"
+	"github.com/gravitational/gamma/internal/schema"
+	"github.com/gravitational/gamma/internal/size"
 	"github.com/gravitational/gamma/internal/utils"
+	"github.com/gravitational/gamma/internal/version"
 	"github.com/gravitational/gamma/internal/workspace"
 	"github.com/jedib0t/go-pretty/v6/text"
 	"github.com/spf13/cobra"
@@ -17,6 +24,13 @@ import (
 var outputDirectory string
 var workingDirectory string
 var assetPaths []string
+var keepDist bool
+var header bool
+var sizeBaseline string
+var sizeOutput string
+var reportFormat string
+var reportFile string
+var allowBreaking bool
 
 var Command = &cobra.Command{
 	Use:   "deploy",
@@ -25,6 +39,20 @@ var Command = &cobra.Command{
 	Run: func(_ *cobra.Command, _ []string) {
 		started := time.Now()
 
+		var format report.Format
+		if reportFormat != "" {
+			f, err := report.ParseFormat(reportFormat)
+			if err != nil {
+				logger.Fatal(err)
+			}
+
+			if reportFile == "" {
+				logger.Fatal("--report-file is required when --report is set")
+			}
+
+			format = f
+		}
+
 		if workingDirectory == "the current working directory" { // this is the default value from the flag
 			wd, err := os.Getwd()
 			if err != nil {
@@ -61,7 +89,16 @@ var Command = &cobra.Command{
 
 		logger.Infof("files changed [%s]", strings.Join(changed, ", "))
 
-		ws := workspace.New(wd, od)
+		commit, err := git.HeadCommit(wd)
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		ws := workspace.New(wd, od, workspace.Options{
+			KeepDist: keepDist,
+			Header:   header,
+			Commit:   commit,
+		})
 
 		logger.Info("collecting actions")
 
@@ -81,6 +118,20 @@ var Command = &cobra.Command{
 
 		logger.Infof("found actions [%s]", strings.Join(actionNames, ", "))
 
+		var baseline size.Baseline
+		if sizeBaseline != "" {
+			b, err := size.ReadBaseline(sizeBaseline)
+			if err != nil {
+				logger.Fatal(err)
+			}
+
+			baseline = b
+		}
+
+		sizes := make(size.Baseline)
+
+		r := report.New("deploy")
+
 		var actionsToBuild []action.Action
 
 	outer:
@@ -92,11 +143,19 @@ var Command = &cobra.Command{
 					continue outer
 				}
 			}
+
+			r.Add(&report.ActionResult{
+				Name:       action.Name(),
+				Repository: fmt.Sprintf("%s/%s", action.Owner(), action.Name()),
+				Status:     report.StatusSkipped,
+			})
 		}
 
 		if len(actionsToBuild) == 0 {
 			logger.Warning("no actions need building, exiting")
 
+			writeReport(r, format)
+
 			return
 		}
 
@@ -105,35 +164,129 @@ var Command = &cobra.Command{
 		for _, action := range actionsToBuild {
 			logger.Infof("action %s has changes, building", action.Name())
 
-			buildStarted := time.Now()
+			result := r.Add(&report.ActionResult{
+				Name:       action.Name(),
+				Repository: fmt.Sprintf("%s/%s", action.Owner(), action.Name()),
+				Status:     report.StatusSuccess,
+			})
+
+			warnings, err := action.CheckRuntime()
+			for _, warning := range warnings {
+				logger.Warningf("action %s: %s", action.Name(), warning)
+			}
 
-			if err := action.Build(); err != nil {
+			if err != nil {
 				hasError = true
-				logger.Errorf("error building action %s: %v", action.Name(), err)
+				result.Fail(err)
+				logger.Errorf("error checking the runtime of action %s: %v", action.Name(), err)
 
 				continue
 			}
 
+			buildStarted := time.Now()
+
+			err = action.Build()
+
 			buildTook := time.Since(buildStarted)
+			result.BuildDuration = buildTook.Seconds()
+
+			if err != nil {
+				hasError = true
+				result.Fail(err)
+				logger.Errorf("error building action %s: %v", action.Name(), err)
+
+				continue
+			}
 
 			logger.Successf("successfully built action %s in %.2fs", action.Name(), buildTook.Seconds())
 
+			previous := baseline[action.Name()]
+			if baseline == nil {
+				deployed, err := repo.GetDeployedSizes(action)
+				if err != nil {
+					logger.Warningf("could not get the deployed sizes of action %s: %v", action.Name(), err)
+				}
+
+				previous = deployed
+			}
+
+			current, err := size.Check(action.Name(), action.OutputDirectory(), action.Config().SizeBudget, previous)
+			if current != nil {
+				sizes[action.Name()] = current
+			}
+
+			if err != nil {
+				hasError = true
+				result.Fail(err)
+				logger.Errorf("not deploying action %s: %v", action.Name(), err)
+
+				continue
+			}
+
+			if err := checkChanges(repo, action); err != nil {
+				hasError = true
+				result.Fail(err)
+				logger.Errorf("not deploying action %s: %v", action.Name(), err)
+
+				continue
+			}
+
 			logger.Infof("deploying action %s", action.Name())
 
+			if err := action.RunHooks(config.PreDeploy); err != nil {
+				hasError = true
+				result.Fail(err)
+				logger.Errorf("error deploying action %s: %v", action.Name(), err)
+
+				continue
+			}
+
 			deployStarted := time.Now()
 
-			if err := repo.DeployAction(action); err != nil {
+			deployment, err := repo.DeployAction(action)
+
+			deployTook := time.Since(deployStarted)
+			result.DeployDuration = deployTook.Seconds()
+
+			if err != nil {
 				hasError = true
+				result.Fail(err)
 				logger.Errorf("error deploying action %s: %v", action.Name(), err)
 
 				continue
 			}
 
-			deployTook := time.Since(deployStarted)
+			result.Repository = deployment.Repository
+			result.SHA = deployment.SHA
+			result.Tag = deployment.Tag
 
 			logger.Successf("successfully deployed action %s in %.2fs", action.Name(), deployTook.Seconds())
+
+			if deployment.TagError != nil {
+				hasError = true
+				result.Fail(deployment.TagError)
+				logger.Errorf("error tagging action %s: %v", action.Name(), deployment.TagError)
+			} else if deployment.Tag != "" {
+				logger.Successf("tagged action %s as %s", action.Name(), deployment.Tag)
+			} else if action.PackageInfo().Version != "" {
+				logger.Warningf("action %s is already tagged as v%s, run gamma version to release its changes", action.Name(), action.PackageInfo().Version)
+			}
+
+			if err := action.RunHooks(config.PostDeploy, "GAMMA_DEPLOYED_SHA="+deployment.SHA, "GAMMA_DEPLOYED_TAG="+deployment.Tag); err != nil {
+				hasError = true
+				result.Fail(err)
+				logger.Errorf("error running hooks for action %s: %v", action.Name(), err)
+			}
+		}
+
+		if sizeOutput != "" {
+			if err := sizes.Write(sizeOutput); err != nil {
+				logger.Error(err)
+			}
 		}
 
+		writeReport(r, format)
+
 		bold := text.Colors{text.FgWhite, text.Bold}
 
 		took := time.Since(started)
@@ -150,4 +303,83 @@ func init() {
 	Command.Flags().StringVarP(&outputDirectory, "output", "o", "build", "output directory")
 	Command.Flags().StringVarP(&workingDirectory, "directory", "d", "the current working directory", "directory containing the monorepo of actions")
 	Command.Flags().StringArrayVarP(&assetPaths, "asset", "a", []string{}, "copy over an asset to each action")
+	Command.Flags().BoolVar(&header, "header", false, "add a comment to each action.yml naming the file and commit it was generated from")
+	Command.Flags().BoolVar(&keepDist, "keep-dist", false, "copy each action's dist into the output directory instead of moving it")
+	Command.Flags().StringVar(&sizeBaseline, "size-baseline", "", "JSON file of previous output sizes to compare against")
+	Command.Flags().StringVar(&sizeOutput, "size-output", "", "write the output sizes of each action to a JSON file, for use as a baseline")
+	Command.Flags().StringVar(&reportFormat, "report", "", "write a machine-readable report (json or junit)")
+	Command.Flags().StringVar(&reportFile, "report-file", "", "file to write the report to")
+	Command.Flags().BoolVar(&allowBreaking, "allow-breaking", false, "deploy breaking changes to an action's interface without a major version bump")
+}
+
+// checkChanges compares the interface of an action with the one deployed, and fails when it has breaking
+// changes without a major bump from the deployed version to the one in its package.json.
+func checkChanges(repo git.Git, a action.Action) error {
+	contents, err := repo.GetDeployedFile(a, "action.yml")
+	if err != nil {
+		return err
+	}
+
+	// nothing is deployed yet, so nothing can break
+	if contents == nil {
+		return nil
+	}
+
+	deployed, err := schema.ParseConfig(fmt.Sprintf("%s/%s/action.yml", a.Owner(), a.Name()), contents)
+	if err != nil {
+		return err
+	}
+
+	current, err := a.Definition()
+	if err != nil {
+		return err
+	}
+
+	changes := schema.Diff(deployed, current)
+
+	for _, change := range changes {
+		if change.Kind == schema.ChangeBreaking {
+			logger.Warningf("action %s: %s change: %s", a.Name(), change.Kind, change.Message)
+		} else {
+			logger.Infof("action %s: %s change: %s", a.Name(), change.Kind, change.Message)
+		}
+	}
+
+	if !schema.HasBreakingChanges(changes) || allowBreaking {
+		return nil
+	}
+
+	previousVersion, err := repo.GetDeployedVersion(a)
+	if err != nil {
+		return err
+	}
+
+	if previousVersion == nil {
+		return errors.New("it has breaking changes, and its deployed version could not be found")
+	}
+
+	currentVersion, err := version.Parse(a.PackageInfo().Version)
+	if err != nil {
+		return err
+	}
+
+	if !version.IsMajorBump(previousVersion, currentVersion) {
+		return fmt.Errorf("it has breaking changes, which need a major version bump from %s, but the version is %s (use --allow-breaking to deploy anyway)", previousVersion, currentVersion)
+	}
+
+	return nil
+}
+
+func writeReport(r *report.Report, format report.Format) {
+	r.Finish()
+
+	if format != "" {
+		if err := r.Write(format, reportFile); err != nil {
+			logger.Error(err)
+		}
+	}
+
+	if err := r.WriteGithubOutput(); err != nil {
+		logger.Error(err)
+	}
 }
@@ -0,0 +1,251 @@
+package inspect
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/gravitational/gamma/internal/git"
+	"github.com/gravitational/gamma/internal/logger"
+	"github.com/gravitational/gamma/internal/schema"
+	"github.com/gravitational/gamma/internal/utils"
+	"github.com/gravitational/gamma/internal/workspace"
+)
+
+var workingDirectory string
+var format string
+
+var Command = &cobra.Command{
+	Use:   "inspect <action>",
+	Short: "Shows where each field of an action comes from",
+	Long:  `Resolves the action.yml of an action and prints it with the file and line each field was defined at, along with the extends that were applied or skipped and the fields that were overridden.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		if format != "yaml" && format != "json" {
+			logger.Fatalf("unsupported format: %s, expected yaml or json", format)
+		}
+
+		if workingDirectory == "the current working directory" { // this is the default value from the flag
+			wd, err := os.Getwd()
+			if err != nil {
+				logger.Fatalf("could not get current working directory: %v", err)
+			}
+
+			workingDirectory = wd
+		}
+
+		wd, _, err := utils.NormalizeDirectories(workingDirectory, "")
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		// the commit is only needed by actions that use ${{ gamma.commit }}, which report it missing themselves
+		commit, _ := git.HeadCommit(wd)
+
+		ws := workspace.New(wd, "", workspace.Options{
+			Commit: commit,
+		})
+
+		actions, err := ws.CollectActions()
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		var config *schema.Config
+
+		for _, action := range actions {
+			if action.Name() != args[0] {
+				continue
+			}
+
+			c, err := action.Inspect()
+			if err != nil {
+				logger.Fatalf("action %s: %v", action.Name(), err)
+			}
+
+			config = c
+		}
+
+		if config == nil {
+			logger.Fatalf("could not find an action named %s", args[0])
+		}
+
+		i := &inspector{wd: wd, config: config}
+
+		var output string
+
+		if format == "json" {
+			output, err = i.json(args[0])
+		} else {
+			output, err = i.yaml()
+		}
+
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		fmt.Print(output)
+	},
+}
+
+func init() {
+	Command.Flags().StringVarP(&workingDirectory, "directory", "d", "the current working directory", "directory containing the monorepo of actions")
+	Command.Flags().StringVarP(&format, "format", "f", "yaml", "output format (yaml or json)")
+}
+
+type inspector struct {
+	wd     string
+	config *schema.Config
+}
+
+func (i *inspector) rel(file string) string {
+	if rel, err := filepath.Rel(i.wd, file); err == nil {
+		return rel
+	}
+
+	return file
+}
+
+func (i *inspector) location(location schema.Location) schema.Location {
+	return schema.Location{
+		File: i.rel(location.File),
+		Line: location.Line,
+	}
+}
+
+func (i *inspector) position(node *yaml.Node) string {
+	location := i.location(i.config.Location(node))
+
+	return fmt.Sprintf("%s:%d", location.File, location.Line)
+}
+
+// yaml prints the resolved config with a comment after each field naming where it was defined, below a
+// summary of the extends and overrides.
+func (i *inspector) yaml() (string, error) {
+	var summary []string
+
+	summary = append(summary, "extends:")
+	for _, extend := range i.config.Extends() {
+		reason := strings.ReplaceAll(extend.Reason, "\n", "; ")
+
+		status := "applied"
+		if extend.Conflict {
+			status = "conflict: " + reason
+		} else if !extend.Applied {
+			status = "skipped: " + reason
+		}
+
+		summary = append(summary, fmt.Sprintf("  %s: %s %s (%s)", i.rel(extend.File), extend.From, extend.Field, status))
+	}
+
+	if len(i.config.Extends()) == 0 {
+		summary = append(summary, "  none")
+	}
+
+	summary = append(summary, "overrides:")
+	for _, override := range i.config.Overrides() {
+		source, overridden := i.location(override.Source), i.location(override.Overridden)
+
+		summary = append(summary, fmt.Sprintf("  %s: %s:%d over %s:%d", override.Field, source.File, source.Line, overridden.File, overridden.Line))
+	}
+
+	if len(i.config.Overrides()) == 0 {
+		summary = append(summary, "  none")
+	}
+
+	var annotate func(node *yaml.Node)
+	annotate = func(node *yaml.Node) {
+		node.HeadComment, node.LineComment, node.FootComment = "", "", ""
+
+		switch node.Kind {
+		case yaml.MappingNode:
+			for j := 0; j+1 < len(node.Content); j += 2 {
+				key, value := node.Content[j], node.Content[j+1]
+
+				annotate(key)
+				annotate(value)
+
+				if value.Kind == yaml.ScalarNode {
+					value.LineComment = i.position(value)
+				} else {
+					key.LineComment = i.position(key)
+				}
+			}
+		case yaml.SequenceNode:
+			for _, child := range node.Content {
+				annotate(child)
+
+				if child.Kind == yaml.ScalarNode {
+					child.LineComment = i.position(child)
+				}
+			}
+		}
+	}
+
+	mapping := i.config.Node.Content[0]
+
+	annotate(mapping)
+
+	contents, err := yaml.Marshal(mapping)
+	if err != nil {
+		return "", fmt.Errorf("could not create yaml: %v", err)
+	}
+
+	for j, line := range summary {
+		summary[j] = "# " + line
+	}
+
+	return strings.Join(summary, "\n") + "\n" + string(contents), nil
+}
+
+func (i *inspector) json(name string) (string, error) {
+	var values map[string]interface{}
+	if err := i.config.Node.Content[0].Decode(&values); err != nil {
+		return "", err
+	}
+
+	output := struct {
+		Action    string                 `json:"action"`
+		Config    map[string]interface{} `json:"config"`
+		Fields    []*schema.Field        `json:"fields"`
+		Extends   []*schema.ExtendResult `json:"extends"`
+		Overrides []*schema.Override     `json:"overrides"`
+	}{
+		Action: name,
+		Config: values,
+	}
+
+	for _, field := range i.config.Fields() {
+		f := *field
+		f.Location = i.location(f.Location)
+
+		output.Fields = append(output.Fields, &f)
+	}
+
+	for _, extend := range i.config.Extends() {
+		e := *extend
+		e.File = i.rel(e.File)
+
+		output.Extends = append(output.Extends, &e)
+	}
+
+	for _, override := range i.config.Overrides() {
+		o := *override
+		o.Source = i.location(o.Source)
+		o.Overridden = i.location(o.Overridden)
+
+		output.Overrides = append(output.Overrides, &o)
+	}
+
+	contents, err := json.MarshalIndent(output, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("could not create json: %v", err)
+	}
+
+	return string(contents) + "\n", nil
+}
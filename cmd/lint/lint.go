@@ -0,0 +1,159 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/gravitational/gamma/internal/git"
+	"github.com/gravitational/gamma/internal/lint"
+	"github.com/gravitational/gamma/internal/logger"
+	"github.com/gravitational/gamma/internal/utils"
+	"github.com/gravitational/gamma/internal/workspace"
+)
+
+var workingDirectory string
+var format string
+
+var Command = &cobra.Command{
+	Use:   "lint",
+	Short: "Lints the interfaces of all the actions",
+	Long:  `Checks the inputs and outputs of every action in the monorepo for common mistakes, such as inputs that are declared but never read, or read but never declared.`,
+	Run: func(_ *cobra.Command, _ []string) {
+		if format != "text" && format != "json" {
+			logger.Fatalf("unsupported format: %s, expected text or json", format)
+		}
+
+		if workingDirectory == "the current working directory" { // this is the default value from the flag
+			wd, err := os.Getwd()
+			if err != nil {
+				logger.Fatalf("could not get current working directory: %v", err)
+			}
+
+			workingDirectory = wd
+		}
+
+		wd, _, err := utils.NormalizeDirectories(workingDirectory, "")
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		// the commit is only needed by actions that use ${{ gamma.commit }}, which report it missing themselves
+		commit, _ := git.HeadCommit(wd)
+
+		ws := workspace.New(wd, "", workspace.Options{
+			Commit: commit,
+		})
+
+		actions, err := ws.CollectActions()
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		if len(actions) == 0 {
+			logger.Fatal("could not find any actions")
+		}
+
+		var targets []*lint.Target
+
+		for _, action := range actions {
+			config, err := action.Definition()
+			if err != nil {
+				logger.Fatalf("action %s: %v", action.Name(), err)
+			}
+
+			target := &lint.Target{
+				Name:               action.Name(),
+				Directory:          action.PackageInfo().Path,
+				GeneratedDirectory: action.Config().CodegenDirectory(),
+				Config:             config,
+			}
+
+			if action.Config().Lint != nil {
+				target.Rules = action.Config().Lint.Rules
+			}
+
+			targets = append(targets, target)
+		}
+
+		findings, err := lint.Run(targets)
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		var errors int
+
+		for _, finding := range findings {
+			if rel, err := filepath.Rel(wd, finding.File); err == nil {
+				finding.File = rel
+			}
+
+			if finding.Severity == lint.SeverityError {
+				errors++
+			}
+		}
+
+		if format == "json" {
+			if findings == nil {
+				findings = []*lint.Finding{}
+			}
+
+			contents, err := json.MarshalIndent(findings, "", "  ")
+			if err != nil {
+				logger.Fatal(err)
+			}
+
+			fmt.Println(string(contents))
+		} else {
+			for _, finding := range findings {
+				message := fmt.Sprintf("%s:%d: %s [%s, %s]", finding.File, finding.Line, finding.Message, finding.Action, finding.Rule)
+
+				if finding.Severity == lint.SeverityError {
+					logger.Error(message)
+				} else {
+					logger.Warning(message)
+				}
+			}
+		}
+
+		if errors > 0 {
+			// keep stdout machine-readable, as the logger writes there too
+			if format == "json" {
+				os.Exit(1)
+			}
+
+			logger.Fatal(text.Colors{text.FgWhite, text.Bold}.Sprintf("found %d %s", errors, plural(errors, "error")))
+		}
+
+		if format == "text" {
+			logger.Success(text.Colors{text.FgWhite, text.Bold}.Sprintf("no lint errors in [%s]", strings.Join(names(targets), ", ")))
+		}
+	},
+}
+
+func init() {
+	Command.Flags().StringVarP(&workingDirectory, "directory", "d", "the current working directory", "directory containing the monorepo of actions")
+	Command.Flags().StringVarP(&format, "format", "f", "text", "output format (text or json)")
+}
+
+func names(targets []*lint.Target) []string {
+	var names []string
+	for _, target := range targets {
+		names = append(names, target.Name)
+	}
+
+	return names
+}
+
+func plural(n int, word string) string {
+	if n == 1 {
+		return word
+	}
+
+	return word + "s"
+}
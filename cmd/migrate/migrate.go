@@ -0,0 +1,153 @@
+package migrate
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gravitational/gamma/internal/logger"
+	"github.com/gravitational/gamma/internal/migrate"
+	"github.com/gravitational/gamma/internal/node"
+	"github.com/gravitational/gamma/internal/schema"
+	"github.com/gravitational/gamma/internal/utils"
+	"github.com/gravitational/gamma/internal/workspace"
+)
+
+var workingDirectory string
+var fromRuntime string
+var dryRun bool
+
+var Command = &cobra.Command{
+	Use:   "migrate",
+	Short: "Migrates the actions in the monorepo",
+	Long:  `Rewrites the actions in the monorepo, and the shared files they extend, in place.`,
+}
+
+var runtimeCommand = &cobra.Command{
+	Use:   "runtime <runtime>",
+	Short: "Bumps the node runtime of all actions",
+	Long:  `Rewrites runs.using in every action.yml and shared file that defines a node runtime, and bumps engines.node in each affected package.json.`,
+	Args:  cobra.ExactArgs(1),
+	Run: func(_ *cobra.Command, args []string) {
+		target := args[0]
+
+		major, err := migrate.ParseRuntime(target)
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		if fromRuntime != "" {
+			if _, err := migrate.ParseRuntime(fromRuntime); err != nil {
+				logger.Fatal(err)
+			}
+		}
+
+		if workingDirectory == "the current working directory" { // this is the default value from the flag
+			wd, err := os.Getwd()
+			if err != nil {
+				logger.Fatalf("could not get current working directory: %v", err)
+			}
+
+			workingDirectory = wd
+		}
+
+		wd, _, err := utils.NormalizeDirectories(workingDirectory, "")
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		ws := workspace.New(wd, "", workspace.Options{})
+
+		logger.Info("collecting actions")
+
+		actions, err := ws.CollectActions()
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		if len(actions) == 0 {
+			logger.Fatal("could not find any actions")
+		}
+
+		var changes []*migrate.Change
+
+		packages := node.NewPackageService(wd)
+
+		visited := make(map[string]*migrate.Change)
+
+		for _, action := range actions {
+			files, err := schema.GetExtendGraph(wd, path.Join(action.PackageInfo().Path, "action.yml"), packages)
+			if err != nil {
+				logger.Fatalf("could not resolve the extend graph of action %s: %v", action.Name(), err)
+			}
+
+			var migrated bool
+
+			for _, file := range files {
+				change, ok := visited[file]
+				if !ok {
+					change, err = migrate.Runtime(file, fromRuntime, target, dryRun)
+					if err != nil {
+						logger.Fatal(err)
+					}
+
+					visited[file] = change
+
+					if change != nil {
+						changes = append(changes, change)
+					}
+				}
+
+				if change != nil {
+					migrated = true
+				}
+			}
+
+			if !migrated {
+				continue
+			}
+
+			change, err := migrate.EnginesNode(path.Join(action.PackageInfo().Path, "package.json"), major, dryRun)
+			if err != nil {
+				logger.Fatal(err)
+			}
+
+			if change != nil {
+				changes = append(changes, change)
+			}
+		}
+
+		if len(changes) == 0 {
+			logger.Warning("no files need migrating")
+
+			return
+		}
+
+		for _, change := range changes {
+			file, err := filepath.Rel(wd, change.File)
+			if err != nil {
+				file = change.File
+			}
+
+			logger.Successf("%s:%d %s -> %s", file, change.Line, change.From, change.To)
+		}
+
+		if dryRun {
+			logger.Infof("%d changes would be made", len(changes))
+
+			return
+		}
+
+		logger.Infof("made %d changes", len(changes))
+	},
+}
+
+func init() {
+	runtimeCommand.Flags().StringVarP(&workingDirectory, "directory", "d", "the current working directory", "directory containing the monorepo of actions")
+	runtimeCommand.Flags().StringVar(&fromRuntime, "from", "", "only migrate actions using this runtime, such as node16")
+	runtimeCommand.Flags().BoolVar(&dryRun, "dry-run", false, "print the changes without writing them")
+
+	Command.AddCommand(runtimeCommand)
+}
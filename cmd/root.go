@@ -4,7 +4,15 @@ import (
 	"github.com/spf13/cobra"
 
 	"github.com/gravitational/gamma/cmd/build"
+	"github.com/gravitational/gamma/cmd/change"
+	"github.com/gravitational/gamma/cmd/codegen"
 	"github.com/gravitational/gamma/cmd/deploy"
+	"github.com/gravitational/gamma/cmd/inspect"
+	"github.com/gravitational/gamma/cmd/lint"
+	"github.com/gravitational/gamma/cmd/migrate"
+	"github.com/gravitational/gamma/cmd/schema"
+	"github.com/gravitational/gamma/cmd/validate"
+	"github.com/gravitational/gamma/cmd/version"
 	"github.com/gravitational/gamma/internal/color"
 )
 
@@ -29,7 +37,15 @@ func init() {
 	cobra.AddTemplateFunc("logo", logo)
 
 	rootCmd.AddCommand(build.Command)
+	rootCmd.AddCommand(change.Command)
+	rootCmd.AddCommand(codegen.Command)
 	rootCmd.AddCommand(deploy.Command)
+	rootCmd.AddCommand(inspect.Command)
+	rootCmd.AddCommand(lint.Command)
+	rootCmd.AddCommand(migrate.Command)
+	rootCmd.AddCommand(schema.Command)
+	rootCmd.AddCommand(validate.Command)
+	rootCmd.AddCommand(version.Command)
 
 	rootCmd.SetHelpTemplate(`{{ logo }}
 
@@ -73,8 +89,24 @@ func colorize(s, name string) string {
 	switch s {
 	case build.Command.Name():
 		return color.Magenta(name)
+	case change.Command.Name():
+		return color.Yellow(name)
+	case codegen.Command.Name():
+		return color.Purple(name)
 	case deploy.Command.Name():
 		return color.Teal(name)
+	case inspect.Command.Name():
+		return color.Yellow(name)
+	case lint.Command.Name():
+		return color.Red(name)
+	case migrate.Command.Name():
+		return color.Green(name)
+	case schema.Command.Name():
+		return color.Teal(name)
+	case validate.Command.Name():
+		return color.White(name)
+	case version.Command.Name():
+		return color.Green(name)
 	case "help":
 		return color.Purple(name)
 	case "completion":
@@ -88,8 +120,24 @@ func emoji(s string) string {
 	switch s {
 	case build.Command.Name():
 		return "🔧"
+	case change.Command.Name():
+		return "📝"
+	case codegen.Command.Name():
+		return "🧬"
 	case deploy.Command.Name():
 		return "🚀"
+	case inspect.Command.Name():
+		return "🔍"
+	case lint.Command.Name():
+		return "🧹"
+	case migrate.Command.Name():
+		return "🚚"
+	case schema.Command.Name():
+		return "📐"
+	case validate.Command.Name():
+		return "✅"
+	case version.Command.Name():
+		return "🔖"
 	case "help":
 		return "❓"
 	case "completion":
@@ -0,0 +1,43 @@
+package schema
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gravitational/gamma/internal/logger"
+	actionschema "github.com/gravitational/gamma/internal/schema"
+)
+
+var output string
+
+var Command = &cobra.Command{
+	Use:   "schema",
+	Short: "Prints the JSON Schema for action.yml files",
+	Long:  `Prints a JSON Schema for gamma's action.yml format, including extend, so editors can validate and complete the action.yml and shared files in the monorepo.`,
+	Run: func(_ *cobra.Command, _ []string) {
+		contents, err := actionschema.JSONSchema()
+		if err != nil {
+			logger.Fatalf("could not create the schema: %v", err)
+		}
+
+		contents = append(contents, '\n')
+
+		if output == "" {
+			fmt.Print(string(contents))
+
+			return
+		}
+
+		if err := os.WriteFile(output, contents, 0644); err != nil {
+			logger.Fatalf("could not write the schema to %s: %v", output, err)
+		}
+
+		logger.Successf("wrote the schema to %s", output)
+	},
+}
+
+func init() {
+	Command.Flags().StringVarP(&output, "output", "o", "", "file to write the schema to, instead of printing it")
+}
@@ -0,0 +1,106 @@
+package validate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/text"
+	"github.com/spf13/cobra"
+
+	"github.com/gravitational/gamma/internal/git"
+	"github.com/gravitational/gamma/internal/logger"
+	"github.com/gravitational/gamma/internal/schema"
+	"github.com/gravitational/gamma/internal/utils"
+	"github.com/gravitational/gamma/internal/workspace"
+)
+
+var workingDirectory string
+
+var Command = &cobra.Command{
+	Use:   "validate",
+	Short: "Validates the metadata of all the actions",
+	Long:  `Resolves the action.yml of every action in the monorepo and validates it against GitHub's metadata rules, without building anything.`,
+	Run: func(_ *cobra.Command, _ []string) {
+		if workingDirectory == "the current working directory" { // this is the default value from the flag
+			wd, err := os.Getwd()
+			if err != nil {
+				logger.Fatalf("could not get current working directory: %v", err)
+			}
+
+			workingDirectory = wd
+		}
+
+		wd, _, err := utils.NormalizeDirectories(workingDirectory, "")
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		// the commit is only needed by actions that use ${{ gamma.commit }}, which report it missing themselves
+		commit, _ := git.HeadCommit(wd)
+
+		ws := workspace.New(wd, "", workspace.Options{
+			Commit: commit,
+		})
+
+		logger.Info("collecting actions")
+
+		actions, err := ws.CollectActions()
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		if len(actions) == 0 {
+			logger.Fatal("could not find any actions")
+		}
+
+		var actionNames []string
+		for _, action := range actions {
+			actionNames = append(actionNames, action.Name())
+		}
+
+		logger.Infof("found actions [%s]", strings.Join(actionNames, ", "))
+
+		var hasError bool
+
+		for _, action := range actions {
+			config, err := action.Definition()
+			if err != nil {
+				hasError = true
+				logger.Errorf("action %s: %v", action.Name(), err)
+
+				continue
+			}
+
+			errs := schema.Validate(config)
+			if len(errs) == 0 {
+				logger.Successf("action %s is valid", action.Name())
+
+				continue
+			}
+
+			hasError = true
+
+			for _, err := range errs {
+				file, rerr := filepath.Rel(wd, err.File)
+				if rerr != nil {
+					file = err.File
+				}
+
+				logger.Errorf("%s:%d: %s [%s]", file, err.Line, err.Message, action.Name())
+			}
+		}
+
+		bold := text.Colors{text.FgWhite, text.Bold}
+
+		if hasError {
+			logger.Fatal(bold.Sprint("validation failed"))
+		}
+
+		logger.Success(bold.Sprint("all actions are valid"))
+	},
+}
+
+func init() {
+	Command.Flags().StringVarP(&workingDirectory, "directory", "d", "the current working directory", "directory containing the monorepo of actions")
+}
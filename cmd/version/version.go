@@ -0,0 +1,102 @@
+package version
+
+import (
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/gravitational/gamma/internal/logger"
+	"github.com/gravitational/gamma/internal/node"
+	"github.com/gravitational/gamma/internal/release"
+	"github.com/gravitational/gamma/internal/utils"
+	"github.com/gravitational/gamma/internal/workspace"
+)
+
+var workingDirectory string
+var dryRun bool
+
+var Command = &cobra.Command{
+	Use:   "version",
+	Short: "Bumps the versions of actions with pending changes",
+	Long:  `Applies the intents recorded with gamma change: bumps the version in each action's package.json, adds the changes to its CHANGELOG.md and deletes the intent files.`,
+	Run: func(_ *cobra.Command, _ []string) {
+		if workingDirectory == "the current working directory" { // this is the default value from the flag
+			wd, err := os.Getwd()
+			if err != nil {
+				logger.Fatalf("could not get current working directory: %v", err)
+			}
+
+			workingDirectory = wd
+		}
+
+		wd, _, err := utils.NormalizeDirectories(workingDirectory, "")
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		intents, err := release.ReadIntents(wd)
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		if len(intents) == 0 {
+			logger.Warning("no pending changes")
+
+			return
+		}
+
+		ws := workspace.New(wd, "", workspace.Options{})
+
+		actions, err := ws.CollectActions()
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		var packages []*node.PackageInfo
+		for _, action := range actions {
+			packages = append(packages, action.PackageInfo())
+		}
+
+		releases, err := release.Plan(packages, intents)
+		if err != nil {
+			logger.Fatal(err)
+		}
+
+		// every release is rendered before any is written, so a broken package.json leaves the monorepo untouched
+		for _, r := range releases {
+			if err := r.Render(); err != nil {
+				logger.Fatalf("action %s: %v", r.Package.Name, err)
+			}
+		}
+
+		if dryRun {
+			for _, r := range releases {
+				logger.Successf("%s: %s -> %s", r.Package.Name, r.Previous, r.Next)
+			}
+
+			logger.Infof("%d actions would be released", len(releases))
+
+			return
+		}
+
+		for _, r := range releases {
+			if err := r.Apply(); err != nil {
+				logger.Fatalf("action %s: %v", r.Package.Name, err)
+			}
+
+			// the intents go along with the release applied, so a later failure does not apply them twice
+			if err := r.Consume(); err != nil {
+				logger.Fatalf("action %s: %v", r.Package.Name, err)
+			}
+
+			logger.Successf("%s: %s -> %s", r.Package.Name, r.Previous, r.Next)
+		}
+
+		logger.Infof("released %d actions, consuming %d changes from %s", len(releases), len(intents), release.Directory)
+	},
+}
+
+func init() {
+	Command.Flags().StringVarP(&workingDirectory, "directory", "d", "the current working directory", "directory containing the monorepo of actions")
+	Command.Flags().BoolVar(&dryRun, "dry-run", false, "print the new versions without writing them")
+}
@@ -14,8 +14,10 @@ import (
 	"golang.org/x/sync/errgroup"
 	"gopkg.in/yaml.v3"
 
+	cfg "github.com/gravitational/gamma/internal/config"
 	"github.com/gravitational/gamma/internal/node"
 	"github.com/gravitational/gamma/internal/schema"
+	"github.com/gravitational/gamma/internal/utils"
 )
 
 type action struct {
@@ -24,6 +26,11 @@ type action struct {
 	outputDirectory  string
 	workingDirectory string
 	owner            string
+	keepDist         bool
+	header           bool
+	commit           string
+	config           *cfg.Config
+	packages         node.PackageService
 }
 
 type Config struct {
@@ -31,6 +38,11 @@ type Config struct {
 	WorkingDirectory string
 	OutputDirectory  string
 	PackageInfo      *node.PackageInfo
+	KeepDist         bool
+	Header           bool
+	Commit           string
+	Gamma            *cfg.Config
+	Packages         node.PackageService
 }
 
 type Action interface {
@@ -38,7 +50,14 @@ type Action interface {
 	Name() string
 	Owner() string
 	OutputDirectory() string
+	PackageInfo() *node.PackageInfo
+	Config() *cfg.Config
 	Contains(filename string) bool
+	Definition() (*schema.Config, error)
+	Inspect() (*schema.Config, error)
+	RunHooks(stage cfg.HookStage, env ...string) error
+	CheckRuntime() ([]string, error)
+	Codegen() ([]string, error)
 }
 
 func New(config *Config) (Action, error) {
@@ -49,12 +68,22 @@ func New(config *Config) (Action, error) {
 
 	parts := strings.Split(uri.Path[1:], "/")
 
+	gamma := config.Gamma
+	if gamma == nil {
+		gamma = &cfg.Config{}
+	}
+
 	return &action{
 		name:             config.Name,
 		packageInfo:      config.PackageInfo,
 		outputDirectory:  config.OutputDirectory,
 		workingDirectory: config.WorkingDirectory,
 		owner:            parts[0],
+		keepDist:         config.KeepDist,
+		header:           config.Header,
+		commit:           config.Commit,
+		config:           gamma,
+		packages:         config.Packages,
 	}, nil
 }
 
@@ -66,6 +95,14 @@ func (a *action) OutputDirectory() string {
 	return a.outputDirectory
 }
 
+func (a *action) PackageInfo() *node.PackageInfo {
+	return a.packageInfo
+}
+
+func (a *action) Config() *cfg.Config {
+	return a.config
+}
+
 func (a *action) Owner() string {
 	return a.owner
 }
@@ -76,6 +113,33 @@ func (a *action) Contains(filename string) bool {
 	return strings.HasPrefix(filename, normalizedPath+"/")
 }
 
+// Definition resolves the action's action.yml, including everything it extends.
+func (a *action) Definition() (*schema.Config, error) {
+	return a.definition(false)
+}
+
+// Inspect resolves the action.yml like Definition, but records the extends that fail to merge as
+// skipped instead of failing.
+func (a *action) Inspect() (*schema.Config, error) {
+	return a.definition(true)
+}
+
+func (a *action) definition(partial bool) (*schema.Config, error) {
+	filename := path.Join(a.packageInfo.Path, "action.yml")
+
+	return schema.GetConfig(a.workingDirectory, filename, schema.Options{
+		Strict:  a.config.IsStrict(),
+		Partial: partial,
+		Variables: map[string]string{
+			"version":    a.packageInfo.Version,
+			"name":       a.packageInfo.Name,
+			"repository": fmt.Sprintf("%s/%s", a.owner, a.packageInfo.Name),
+			"commit":     a.commit,
+		},
+		Packages: a.packages,
+	})
+}
+
 func (a *action) buildPackage() error {
 	cmd := exec.Command("yarn", "build")
 	cmd.Dir = a.packageInfo.Path
@@ -84,15 +148,27 @@ func (a *action) buildPackage() error {
 		return err
 	}
 
-	return a.movePackage()
+	if err := a.movePackage(); err != nil {
+		return err
+	}
+
+	return a.writeLicenses()
 }
 
 func (a *action) movePackage() error {
 	dist := path.Join(a.packageInfo.Path, "dist")
 	destination := path.Join(a.outputDirectory, "dist")
 
-	if err := os.Rename(dist, destination); err != nil {
-		return err
+	if a.keepDist {
+		if err := utils.CopyDirectory(dist, destination); err != nil {
+			return fmt.Errorf("could not copy dist: %v", err)
+		}
+
+		return nil
+	}
+
+	if err := utils.MoveDirectory(dist, destination); err != nil {
+		return fmt.Errorf("could not move dist: %v", err)
 	}
 
 	return nil
@@ -101,12 +177,32 @@ func (a *action) movePackage() error {
 func (a *action) createActionYAML() error {
 	filename := path.Join(a.packageInfo.Path, "action.yml")
 
-	definition, err := schema.GetConfig(a.workingDirectory, filename)
+	definition, err := a.Definition()
 	if err != nil {
 		return err
 	}
 
-	bytes, err := yaml.Marshal(definition)
+	document := definition.Published()
+
+	if a.header {
+		source, err := filepath.Rel(a.workingDirectory, filename)
+		if err != nil {
+			source = filename
+		}
+
+		header := fmt.Sprintf("Generated by gamma from %s", source)
+		if a.commit != "" {
+			header += fmt.Sprintf(" at commit %s", a.commit)
+		}
+
+		if document.HeadComment != "" {
+			header += "\n\n" + document.HeadComment
+		}
+
+		document.HeadComment = header
+	}
+
+	bytes, err := yaml.Marshal(document)
 	if err != nil {
 		return err
 	}
@@ -166,7 +262,7 @@ func (a *action) copyFiles() error {
 		return err
 	}
 
-	return nil
+	return a.renderDocs()
 }
 
 func (a *action) createOutputDirectory() error {
@@ -178,10 +274,20 @@ func (a *action) createOutputDirectory() error {
 }
 
 func (a *action) Build() error {
+	if err := a.RunHooks(cfg.PreBuild); err != nil {
+		return err
+	}
+
 	if err := a.createOutputDirectory(); err != nil {
 		return fmt.Errorf("could not create output directory: %v", err)
 	}
 
+	if a.config.Codegen != nil && a.config.Codegen.Build != nil && *a.config.Codegen.Build {
+		if _, err := a.Codegen(); err != nil {
+			return fmt.Errorf("could not generate code: %v", err)
+		}
+	}
+
 	var eg errgroup.Group
 
 	eg.Go(a.buildPackage)
@@ -192,5 +298,5 @@ func (a *action) Build() error {
 		return err
 	}
 
-	return nil
+	return a.RunHooks(cfg.PostBuild)
 }
@@ -0,0 +1,51 @@
+package action
+
+import (
+	"fmt"
+	"os"
+	"path"
+
+	"github.com/gravitational/gamma/internal/codegen"
+)
+
+// Codegen writes inputs.ts and outputs.ts for a Javascript action, returning the files it wrote.
+func (a *action) Codegen() ([]string, error) {
+	definition, err := a.Definition()
+	if err != nil {
+		return nil, err
+	}
+
+	if definition.Runs.JavascriptRun == nil {
+		return nil, nil
+	}
+
+	inputs, err := codegen.Inputs(definition)
+	if err != nil {
+		return nil, err
+	}
+
+	outputs, err := codegen.Outputs(definition)
+	if err != nil {
+		return nil, err
+	}
+
+	directory := path.Join(a.packageInfo.Path, a.config.CodegenDirectory())
+
+	if err := os.MkdirAll(directory, 0755); err != nil {
+		return nil, fmt.Errorf("could not create %s: %v", directory, err)
+	}
+
+	var files []string
+
+	for _, module := range []struct{ name, contents string }{{"inputs.ts", inputs}, {"outputs.ts", outputs}} {
+		file := path.Join(directory, module.name)
+
+		if err := os.WriteFile(file, []byte(module.contents), 0644); err != nil {
+			return nil, fmt.Errorf("could not write %s: %v", file, err)
+		}
+
+		files = append(files, file)
+	}
+
+	return files, nil
+}
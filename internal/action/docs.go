@@ -0,0 +1,59 @@
+package action
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// renderDocs fills in the sections of the action's README between <!-- gamma:inputs --> and
+// <!-- /gamma:inputs -->, or the same for outputs, with tables generated from its action.yml.
+func (a *action) renderDocs() error {
+	filename := path.Join(a.outputDirectory, "README.md")
+
+	contents, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	readme := string(contents)
+	if !strings.Contains(readme, "<!-- gamma:inputs -->") && !strings.Contains(readme, "<!-- gamma:outputs -->") {
+		return nil
+	}
+
+	definition, err := a.Definition()
+	if err != nil {
+		return err
+	}
+
+	readme = replaceSection(readme, "inputs", definition.InputsMarkdown())
+	readme = replaceSection(readme, "outputs", definition.OutputsMarkdown())
+
+	if err := os.WriteFile(filename, []byte(readme), 0644); err != nil {
+		return fmt.Errorf("could not write README.md: %v", err)
+	}
+
+	return nil
+}
+
+func replaceSection(contents, name, section string) string {
+	start := fmt.Sprintf("<!-- gamma:%s -->", name)
+	end := fmt.Sprintf("<!-- /gamma:%s -->", name)
+
+	before, rest, ok := strings.Cut(contents, start)
+	if !ok {
+		return contents
+	}
+
+	// a section without an end marker yet keeps everything after it
+	_, after, ok := strings.Cut(rest, end)
+	if !ok {
+		after = rest
+	}
+
+	return before + start + "\n\n" + section + "\n" + end + "\n" + strings.TrimPrefix(after, "\n")
+}
@@ -0,0 +1,37 @@
+package action
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	cfg "github.com/gravitational/gamma/internal/config"
+)
+
+// RunHooks runs the action's commands for a lifecycle stage in its package directory. Extra
+// environment variables are given as KEY=value pairs.
+func (a *action) RunHooks(stage cfg.HookStage, env ...string) error {
+	for _, command := range a.config.Hooks.Get(stage) {
+		cmd := exec.Command("sh", "-c", command)
+		cmd.Dir = a.packageInfo.Path
+		cmd.Env = append(append(os.Environ(), a.hookEnv()...), env...)
+
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("%s hook %q failed: %v\n%s", stage, command, err, strings.TrimSpace(string(output)))
+		}
+	}
+
+	return nil
+}
+
+func (a *action) hookEnv() []string {
+	return []string{
+		"GAMMA_ACTION_NAME=" + a.Name(),
+		"GAMMA_ACTION_DIRECTORY=" + a.packageInfo.Path,
+		"GAMMA_OUTPUT_DIRECTORY=" + a.outputDirectory,
+		"GAMMA_REPOSITORY=" + a.owner + "/" + a.Name(),
+		"GAMMA_VERSION=" + a.packageInfo.Version,
+	}
+}
@@ -0,0 +1,192 @@
+package action
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gravitational/gamma/internal/node"
+)
+
+var licenseFilePrefixes = []string{"license", "licence", "copying"}
+
+func (a *action) writeLicenses() error {
+	packages := node.NewPackageService(a.workingDirectory)
+
+	dependencies, err := packages.GetDependencies(a.packageInfo)
+	if err != nil {
+		return err
+	}
+
+	var disallowed []string
+	if a.config.Licenses != nil {
+		disallowed = a.config.Licenses.Disallowed
+	}
+
+	var sb strings.Builder
+	var violations []string
+
+	for _, d := range dependencies {
+		if d.Private {
+			continue
+		}
+
+		spdx := d.SPDX()
+		if spdx == "" {
+			spdx = "UNKNOWN"
+		}
+
+		if isDisallowed(spdx, disallowed) {
+			violations = append(violations, fmt.Sprintf("%s@%s (%s)", d.Name, d.Version, spdx))
+		}
+
+		text, err := readLicenseText(d.Path)
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(&sb, "%s@%s\n%s\n", d.Name, d.Version, spdx)
+
+		if text != "" {
+			fmt.Fprintf(&sb, "%s\n", strings.TrimSpace(text))
+		}
+
+		sb.WriteString("\n")
+	}
+
+	if len(violations) > 0 {
+		return fmt.Errorf("dependencies use disallowed licenses: %s", strings.Join(violations, ", "))
+	}
+
+	output := path.Join(a.outputDirectory, "dist", "licenses.txt")
+	if err := os.WriteFile(output, []byte(sb.String()), 0644); err != nil {
+		return fmt.Errorf("could not create licenses.txt: %v", err)
+	}
+
+	return nil
+}
+
+func readLicenseText(dir string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("could not read %s: %v", dir, err)
+	}
+
+	var files []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := strings.ToLower(entry.Name())
+		for _, prefix := range licenseFilePrefixes {
+			if strings.HasPrefix(name, prefix) {
+				files = append(files, entry.Name())
+
+				break
+			}
+		}
+	}
+
+	sort.Strings(files)
+
+	var texts []string
+	for _, file := range files {
+		contents, err := os.ReadFile(path.Join(dir, file))
+		if err != nil {
+			return "", fmt.Errorf("could not read %s: %v", file, err)
+		}
+
+		texts = append(texts, strings.TrimSpace(string(contents)))
+	}
+
+	return strings.Join(texts, "\n\n"), nil
+}
+
+// isDisallowed reports whether an SPDX expression only permits disallowed licenses. AND binds tighter
+// than OR and parentheses group, so an expression is allowed if any of its alternatives is made up
+// solely of allowed licenses.
+func isDisallowed(expression string, disallowed []string) bool {
+	if len(disallowed) == 0 {
+		return false
+	}
+
+	e := &spdxExpression{
+		tokens:     strings.Fields(strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expression)),
+		disallowed: disallowed,
+	}
+
+	return !e.or()
+}
+
+// spdxExpression evaluates whether an SPDX expression is allowed while parsing it.
+type spdxExpression struct {
+	tokens     []string
+	disallowed []string
+}
+
+func (e *spdxExpression) or() bool {
+	allowed := e.and()
+
+	for e.next("OR") {
+		if e.and() {
+			allowed = true
+		}
+	}
+
+	return allowed
+}
+
+func (e *spdxExpression) and() bool {
+	allowed := e.license()
+
+	for e.next("AND") {
+		if !e.license() {
+			allowed = false
+		}
+	}
+
+	return allowed
+}
+
+func (e *spdxExpression) license() bool {
+	if e.next("(") {
+		allowed := e.or()
+		e.next(")")
+
+		return allowed
+	}
+
+	if len(e.tokens) == 0 {
+		return true
+	}
+
+	license := e.tokens[0]
+	e.tokens = e.tokens[1:]
+
+	// an exception only adds permissions to the license it applies to
+	if e.next("WITH") && len(e.tokens) > 0 {
+		e.tokens = e.tokens[1:]
+	}
+
+	for _, d := range e.disallowed {
+		if strings.EqualFold(license, d) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// next consumes the next token if it is the given operator or parenthesis.
+func (e *spdxExpression) next(token string) bool {
+	if len(e.tokens) == 0 || !strings.EqualFold(e.tokens[0], token) {
+		return false
+	}
+
+	e.tokens = e.tokens[1:]
+
+	return true
+}
@@ -0,0 +1,36 @@
+package action
+
+import "testing"
+
+func TestIsDisallowed(t *testing.T) {
+	disallowed := []string{"GPL-3.0-only", "AGPL-3.0-only"}
+
+	tests := []struct {
+		expression string
+		disallowed []string
+		want       bool
+	}{
+		{"MIT", disallowed, false},
+		{"GPL-3.0-only", disallowed, true},
+		{"gpl-3.0-only", disallowed, true},
+		{"GPL-3.0-only", nil, false},
+		{"MIT OR GPL-3.0-only", disallowed, false},
+		{"MIT AND GPL-3.0-only", disallowed, true},
+		{"GPL-3.0-only OR AGPL-3.0-only", disallowed, true},
+		{"MIT AND (GPL-3.0-only OR Apache-2.0)", disallowed, false},
+		{"(MIT AND GPL-3.0-only) OR Apache-2.0", disallowed, false},
+		{"MIT AND GPL-3.0-only OR AGPL-3.0-only", disallowed, true},
+		{"Apache-2.0 OR MIT AND GPL-3.0-only", disallowed, false},
+		{"(GPL-3.0-only OR AGPL-3.0-only) AND MIT", disallowed, true},
+		{"GPL-3.0-only WITH Classpath-exception-2.0", disallowed, true},
+		{"GPL-2.0-only WITH Classpath-exception-2.0", disallowed, false},
+		{"((MIT))", disallowed, false},
+		{"UNKNOWN", []string{"UNKNOWN"}, true},
+	}
+
+	for _, test := range tests {
+		if got := isDisallowed(test.expression, test.disallowed); got != test.want {
+			t.Errorf("isDisallowed(%q, %v) = %t, want %t", test.expression, test.disallowed, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package action
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var deprecatedRuntimes = map[string]bool{
+	"node12": true,
+	"node16": true,
+}
+
+// runtimeTargets is the newest ECMAScript version fully supported by each node runtime.
+var runtimeTargets = map[int]int{
+	12: 2019,
+	16: 2021,
+	20: 2023,
+	24: 2024,
+}
+
+var esVersion = regexp.MustCompile(`^ES(\d+)$`)
+
+// CheckRuntime compares the node runtime in action.yml with engines.node in package.json and the
+// TypeScript and ncc compilation targets. Mismatches are returned as warnings, or as an error when
+// the runtime check is configured as "error".
+func (a *action) CheckRuntime() ([]string, error) {
+	mode := a.config.RuntimeCheck
+	if mode == "" {
+		mode = "warn"
+	}
+
+	if mode == "off" {
+		return nil, nil
+	}
+
+	if mode != "warn" && mode != "error" {
+		return nil, fmt.Errorf("unsupported runtimeCheck value: %s, expected warn, error or off", mode)
+	}
+
+	definition, err := a.Definition()
+	if err != nil {
+		return nil, err
+	}
+
+	if definition.Runs.JavascriptRun == nil {
+		return nil, nil
+	}
+
+	using := definition.Runs.JavascriptRun.Using
+
+	var warnings []string
+	if deprecatedRuntimes[using] {
+		warnings = append(warnings, fmt.Sprintf("%s is deprecated by GitHub, use node20 or newer", using))
+	}
+
+	runtime, err := strconv.Atoi(strings.TrimPrefix(using, "node"))
+	if err != nil {
+		return warnings, fmt.Errorf("could not parse runs.using value: %s", using)
+	}
+
+	var mismatches []string
+
+	if allowed, ok := a.packageInfo.AllowsNodeMajor(runtime); ok && !allowed {
+		mismatches = append(mismatches, fmt.Sprintf("runs.using is %s but engines.node in package.json is %s, which does not allow it", using, a.packageInfo.Engines["node"]))
+	}
+
+	tsTarget, err := a.packageInfo.TypeScriptTarget()
+	if err != nil {
+		return warnings, err
+	}
+
+	if mismatch := checkTarget(runtime, "the TypeScript target", tsTarget); mismatch != "" {
+		mismatches = append(mismatches, mismatch)
+	}
+
+	if mismatch := checkTarget(runtime, "the ncc target", a.packageInfo.NCCTarget()); mismatch != "" {
+		mismatches = append(mismatches, mismatch)
+	}
+
+	if mode == "error" && len(mismatches) > 0 {
+		return warnings, errors.New(strings.Join(mismatches, ", "))
+	}
+
+	return append(warnings, mismatches...), nil
+}
+
+func checkTarget(runtime int, name, target string) string {
+	match := esVersion.FindStringSubmatch(strings.ToUpper(target))
+	if match == nil {
+		return ""
+	}
+
+	version, _ := strconv.Atoi(match[1])
+	if version < 2015 {
+		// ES5 and ES6 style targets are supported by every runtime
+		return ""
+	}
+
+	supported, ok := runtimeTargets[runtime]
+	if !ok || version <= supported {
+		return ""
+	}
+
+	return fmt.Sprintf("%s is %s but node%d only supports up to ES%d", name, target, runtime, supported)
+}
@@ -0,0 +1,275 @@
+package codegen
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gravitational/gamma/internal/schema"
+)
+
+const header = "// Code generated by gamma from action.yml. DO NOT EDIT.\n"
+
+// IsGenerated returns whether a file's contents are a module generated by gamma.
+func IsGenerated(contents []byte) bool {
+	return bytes.HasPrefix(contents, []byte(header))
+}
+
+// helpers are the functions inputs.ts uses to parse typed inputs, only written when used so the module
+// compiles with noUnusedLocals.
+var helpers = map[string]string{
+	"optional": `function optional<T>(value: string, parse: (value: string) => T): T | undefined {
+  return value === '' ? undefined : parse(value);
+}`,
+	"parseBoolean": `function parseBoolean(name: string, value: string): boolean {
+  if (['true', 'True', 'TRUE'].includes(value)) {
+    return true;
+  }
+
+  if (['false', 'False', 'FALSE'].includes(value)) {
+    return false;
+  }
+
+  throw new TypeError(` + "`Input ${name} needs to be true or false, got ${value}`" + `);
+}`,
+	"parseNumber": `function parseNumber(name: string, value: string): number {
+  const number = Number(value);
+
+  if (value.trim() === '' || Number.isNaN(number)) {
+    throw new TypeError(` + "`Input ${name} needs to be a number, got ${value}`" + `);
+  }
+
+  return number;
+}`,
+	"parseEnum": `function parseEnum<T extends string>(name: string, value: string, choices: readonly T[]): T {
+  if (!(choices as readonly string[]).includes(value)) {
+    throw new TypeError(` + "`Input ${name} needs to be one of ${choices.join(', ')}, got ${value}`" + `);
+  }
+
+  return value as T;
+}`,
+	"parseList": `function parseList(value: string): string[] {
+  return value
+    .split(/[\n,]/)
+    .map(item => item.trim())
+    .filter(item => item !== '');
+}`,
+	"matchPattern": `function matchPattern(name: string, value: string, pattern: RegExp): string {
+  if (!pattern.test(value)) {
+    throw new TypeError(` + "`Input ${name} needs to match ${pattern}, got ${value}`" + `);
+  }
+
+  return value;
+}`,
+}
+
+// Inputs generates a module with an Inputs interface and a getInputs function, which reads and parses
+// every input of the action.
+func Inputs(config *schema.Config) (string, error) {
+	names := config.InputNames()
+
+	properties, err := identifiers(names, "input")
+	if err != nil {
+		return "", err
+	}
+
+	used := make(map[string]bool)
+
+	var fields, values []string
+
+	for i, name := range names {
+		input := (*config.Inputs)[name]
+
+		required := input.Required != nil && *input.Required
+
+		options := ""
+		if required {
+			options = ", { required: true }"
+		}
+
+		raw := fmt.Sprintf("core.getInput(%s%s)", quote(name), options)
+
+		tsType, value := parseInput(name, &input, raw, used)
+
+		// GitHub passes the default when an input is not set, so only inputs without either can be missing
+		if !required && (input.Default == nil || *input.Default == "") {
+			tsType += " | undefined"
+
+			if value == raw {
+				value = raw + " || undefined"
+			} else {
+				used["optional"] = true
+
+				_, parsed := parseInput(name, &input, "value", used)
+				value = fmt.Sprintf("optional(%s, value => %s)", raw, parsed)
+			}
+		}
+
+		fields = append(fields, comment(input.Description)+fmt.Sprintf("  %s: %s;", properties[i], tsType))
+		values = append(values, fmt.Sprintf("    %s: %s,", properties[i], value))
+	}
+
+	var b strings.Builder
+
+	b.WriteString(header + "\n")
+
+	if len(names) > 0 {
+		b.WriteString("import * as core from '@actions/core';\n\n")
+	}
+
+	b.WriteString("export interface Inputs {\n")
+	for _, field := range fields {
+		b.WriteString(field + "\n")
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("export function getInputs(): Inputs {\n  return {\n")
+	for _, value := range values {
+		b.WriteString(value + "\n")
+	}
+	b.WriteString("  };\n}\n")
+
+	var helperNames []string
+	for name := range used {
+		helperNames = append(helperNames, name)
+	}
+
+	sort.Strings(helperNames)
+
+	for _, name := range helperNames {
+		b.WriteString("\n" + helpers[name] + "\n")
+	}
+
+	return b.String(), nil
+}
+
+// parseInput returns the TypeScript type of an input, and the expression parsing it from value.
+func parseInput(name string, input *schema.Input, value string, used map[string]bool) (string, string) {
+	if input.Pattern != nil && input.InputType() != schema.InputTypeList {
+		used["matchPattern"] = true
+
+		value = fmt.Sprintf("matchPattern(%s, %s, new RegExp(%s))", quote(name), value, quote(*input.Pattern))
+	}
+
+	switch input.InputType() {
+	case schema.InputTypeBoolean:
+		used["parseBoolean"] = true
+
+		return "boolean", fmt.Sprintf("parseBoolean(%s, %s)", quote(name), value)
+	case schema.InputTypeNumber:
+		used["parseNumber"] = true
+
+		return "number", fmt.Sprintf("parseNumber(%s, %s)", quote(name), value)
+	case schema.InputTypeEnum:
+		used["parseEnum"] = true
+
+		var choices []string
+		for _, choice := range *input.Choices {
+			choices = append(choices, quote(choice))
+		}
+
+		return strings.Join(choices, " | "), fmt.Sprintf("parseEnum(%s, %s, [%s] as const)", quote(name), value, strings.Join(choices, ", "))
+	case schema.InputTypeList:
+		used["parseList"] = true
+
+		if input.Pattern != nil {
+			used["matchPattern"] = true
+
+			return "string[]", fmt.Sprintf("parseList(%s).map(item => matchPattern(%s, item, new RegExp(%s)))", value, quote(name), quote(*input.Pattern))
+		}
+
+		return "string[]", fmt.Sprintf("parseList(%s)", value)
+	}
+
+	return "string", value
+}
+
+// Outputs generates a module with an Outputs interface and functions to set them by their property names.
+func Outputs(config *schema.Config) (string, error) {
+	names := config.OutputNames()
+
+	properties, err := identifiers(names, "output")
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+
+	b.WriteString(header + "\n")
+	b.WriteString("import * as core from '@actions/core';\n\n")
+
+	b.WriteString("export interface Outputs {\n")
+	for i, name := range names {
+		b.WriteString(comment((*config.Outputs)[name].Description) + fmt.Sprintf("  %s: string;\n", properties[i]))
+	}
+	b.WriteString("}\n\n")
+
+	b.WriteString("const names: Record<keyof Outputs, string> = {\n")
+	for i, name := range names {
+		b.WriteString(fmt.Sprintf("  %s: %s,\n", properties[i], quote(name)))
+	}
+	b.WriteString("};\n\n")
+
+	b.WriteString(`export function setOutput<K extends keyof Outputs>(name: K, value: Outputs[K]): void {
+  core.setOutput(names[name], value);
+}
+
+export function setOutputs(outputs: Partial<Outputs>): void {
+  for (const [name, value] of Object.entries(outputs) as [keyof Outputs, string][]) {
+    core.setOutput(names[name], value);
+  }
+}
+`)
+
+	return b.String(), nil
+}
+
+// identifiers turns input or output IDs such as dry-run into property names such as dryRun.
+func identifiers(names []string, kind string) ([]string, error) {
+	var properties []string
+
+	seen := make(map[string]string)
+
+	for _, name := range names {
+		property := Property(name)
+
+		if previous, ok := seen[property]; ok {
+			return nil, fmt.Errorf("%ss %s and %s would both be named %s", kind, previous, name, property)
+		}
+
+		seen[property] = name
+		properties = append(properties, property)
+	}
+
+	return properties, nil
+}
+
+// Property returns the property name of an input or output ID in the generated modules.
+func Property(name string) string {
+	parts := strings.Split(name, "-")
+	for i := 1; i < len(parts); i++ {
+		if parts[i] != "" {
+			parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+		}
+	}
+
+	return strings.Join(parts, "")
+}
+
+func comment(description string) string {
+	description = strings.TrimSpace(description)
+	if description == "" {
+		return ""
+	}
+
+	description = strings.ReplaceAll(description, "*/", "*\\/")
+
+	return fmt.Sprintf("  /** %s */\n", strings.ReplaceAll(description, "\n", "\n   * "))
+}
+
+func quote(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`)
+
+	return "'" + replacer.Replace(s) + "'"
+}
@@ -0,0 +1,135 @@
+package codegen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gravitational/gamma/internal/schema"
+)
+
+func TestInputs(t *testing.T) {
+	tests := []struct {
+		name     string
+		inputs   string
+		contains []string
+		excludes []string
+		err      string
+	}{
+		{
+			name: "strings",
+			inputs: `
+  version:
+    description: The version
+    required: true
+  dry-run:
+    description: Skip the upload
+`,
+			contains: []string{
+				"  /** The version */\n  version: string;",
+				"  dryRun: string | undefined;",
+				"    version: core.getInput('version', { required: true }),",
+				"    dryRun: core.getInput('dry-run') || undefined,",
+			},
+			excludes: []string{"function optional"},
+		},
+		{
+			name: "defaults are never missing",
+			inputs: `
+  retries:
+    description: How often to retry
+    type: number
+    default: "3"
+`,
+			contains: []string{
+				"  retries: number;",
+				"    retries: parseNumber('retries', core.getInput('retries')),",
+				"function parseNumber(",
+			},
+			excludes: []string{"function optional", "function parseBoolean"},
+		},
+		{
+			name: "optional typed inputs",
+			inputs: `
+  debug:
+    description: Log more
+    type: boolean
+  level:
+    description: How much to log
+    type: enum
+    choices: [debug, info]
+`,
+			contains: []string{
+				"  debug: boolean | undefined;",
+				"    debug: optional(core.getInput('debug'), value => parseBoolean('debug', value)),",
+				"  level: 'debug' | 'info' | undefined;",
+				"parseEnum('level', value, ['debug', 'info'] as const)",
+				"function optional<T>(",
+			},
+		},
+		{
+			name: "lists with a pattern",
+			inputs: `
+  tags:
+    description: The tags
+    type: list
+    pattern: ^v
+    required: true
+`,
+			contains: []string{
+				"  tags: string[];",
+				"parseList(core.getInput('tags', { required: true })).map(item => matchPattern('tags', item, new RegExp('^v')))",
+			},
+		},
+		{
+			name: "colliding property names",
+			inputs: `
+  dry-run:
+    description: Skip the upload
+  dryRun:
+    description: Skip the upload
+`,
+			err: "inputs dry-run and dryRun would both be named dryRun",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			contents := "name: action\ndescription: action\ninputs:" + test.inputs + "runs:\n  using: node20\n  main: dist/index.js\n"
+
+			config, err := schema.ParseConfig("action.yml", []byte(contents))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			module, err := Inputs(config)
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected an error containing %q, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !strings.HasPrefix(module, header) {
+				t.Errorf("expected the module to start with the generated header, got:\n%s", module)
+			}
+
+			for _, s := range test.contains {
+				if !strings.Contains(module, s) {
+					t.Errorf("expected the module to contain %q, got:\n%s", s, module)
+				}
+			}
+
+			for _, s := range test.excludes {
+				if strings.Contains(module, s) {
+					t.Errorf("expected the module not to contain %q, got:\n%s", s, module)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package config
+
+// Config is gamma's own configuration, read from the "gamma" field of the root package.json
+// and of each action's package.json. Values set by an action take precedence over the root.
+type Config struct {
+	SizeBudget *SizeBudget `json:"sizeBudget,omitempty"`
+	Licenses   *Licenses   `json:"licenses,omitempty"`
+	Hooks      *Hooks      `json:"hooks,omitempty"`
+	// RuntimeCheck is either "warn" (the default), "error" or "off", and controls what happens when
+	// an action's runs.using does not match its engines.node or compilation target.
+	RuntimeCheck string `json:"runtimeCheck,omitempty"`
+	// Strict rejects unknown keys in action.yml and shared files, and is on by default
+	Strict  *bool    `json:"strict,omitempty"`
+	Codegen *Codegen `json:"codegen,omitempty"`
+	Lint    *Lint    `json:"lint,omitempty"`
+}
+
+// Lint configures the rules of gamma lint, mapping each rule to error, warning or off.
+type Lint struct {
+	Rules map[string]string `json:"rules,omitempty"`
+}
+
+// Codegen configures the TypeScript modules generated from an action's inputs and outputs.
+type Codegen struct {
+	// Directory is where inputs.ts and outputs.ts are written, relative to the action
+	Directory string `json:"directory,omitempty"`
+	// Build generates the modules before each build
+	Build *bool `json:"build,omitempty"`
+}
+
+// CodegenDirectory returns the directory generated modules are written to, relative to the action.
+func (c *Config) CodegenDirectory() string {
+	if c.Codegen == nil || c.Codegen.Directory == "" {
+		return "src/generated"
+	}
+
+	return c.Codegen.Directory
+}
+
+func (c *Config) IsStrict() bool {
+	return c.Strict == nil || *c.Strict
+}
+
+type Licenses struct {
+	Disallowed []string `json:"disallowed,omitempty"`
+}
+
+type SizeBudget struct {
+	Total    *Size           `json:"total,omitempty"`
+	Increase *Size           `json:"increase,omitempty"`
+	Files    map[string]Size `json:"files,omitempty"`
+}
+
+func Merge(root, action *Config) *Config {
+	merged := &Config{}
+
+	for _, c := range []*Config{root, action} {
+		if c == nil {
+			continue
+		}
+
+		merged.SizeBudget = mergeSizeBudget(merged.SizeBudget, c.SizeBudget)
+		merged.Licenses = mergeLicenses(merged.Licenses, c.Licenses)
+		merged.Hooks = mergeHooks(merged.Hooks, c.Hooks)
+
+		if c.RuntimeCheck != "" {
+			merged.RuntimeCheck = c.RuntimeCheck
+		}
+
+		if c.Strict != nil {
+			merged.Strict = c.Strict
+		}
+
+		merged.Codegen = mergeCodegen(merged.Codegen, c.Codegen)
+		merged.Lint = mergeLint(merged.Lint, c.Lint)
+	}
+
+	return merged
+}
+
+func mergeSizeBudget(base, override *SizeBudget) *SizeBudget {
+	if override == nil {
+		return base
+	}
+
+	if base == nil {
+		return override
+	}
+
+	merged := &SizeBudget{
+		Total:    base.Total,
+		Increase: base.Increase,
+		Files:    make(map[string]Size),
+	}
+
+	if override.Total != nil {
+		merged.Total = override.Total
+	}
+
+	if override.Increase != nil {
+		merged.Increase = override.Increase
+	}
+
+	for pattern, size := range base.Files {
+		merged.Files[pattern] = size
+	}
+
+	for pattern, size := range override.Files {
+		merged.Files[pattern] = size
+	}
+
+	return merged
+}
+
+func mergeLicenses(base, override *Licenses) *Licenses {
+	if override == nil {
+		return base
+	}
+
+	if base == nil {
+		return override
+	}
+
+	return &Licenses{
+		Disallowed: append(append([]string{}, base.Disallowed...), override.Disallowed...),
+	}
+}
+
+func mergeCodegen(base, override *Codegen) *Codegen {
+	if override == nil {
+		return base
+	}
+
+	if base == nil {
+		return override
+	}
+
+	merged := *base
+
+	if override.Directory != "" {
+		merged.Directory = override.Directory
+	}
+
+	if override.Build != nil {
+		merged.Build = override.Build
+	}
+
+	return &merged
+}
+
+func mergeLint(base, override *Lint) *Lint {
+	if override == nil {
+		return base
+	}
+
+	if base == nil {
+		return override
+	}
+
+	merged := &Lint{Rules: make(map[string]string)}
+
+	for rule, severity := range base.Rules {
+		merged.Rules[rule] = severity
+	}
+
+	for rule, severity := range override.Rules {
+		merged.Rules[rule] = severity
+	}
+
+	return merged
+}
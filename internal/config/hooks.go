@@ -0,0 +1,80 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type HookStage string
+
+const (
+	PreBuild   HookStage = "preBuild"
+	PostBuild  HookStage = "postBuild"
+	PreDeploy  HookStage = "preDeploy"
+	PostDeploy HookStage = "postDeploy"
+)
+
+// Commands is a list of shell commands, configured either as a single string or as an array.
+type Commands []string
+
+func (c *Commands) UnmarshalJSON(data []byte) error {
+	var command string
+	if err := json.Unmarshal(data, &command); err == nil {
+		*c = Commands{command}
+
+		return nil
+	}
+
+	var commands []string
+	if err := json.Unmarshal(data, &commands); err != nil {
+		return fmt.Errorf("could not parse hook, expected a command or a list of commands: %s", data)
+	}
+
+	*c = commands
+
+	return nil
+}
+
+type Hooks struct {
+	PreBuild   Commands `json:"preBuild,omitempty"`
+	PostBuild  Commands `json:"postBuild,omitempty"`
+	PreDeploy  Commands `json:"preDeploy,omitempty"`
+	PostDeploy Commands `json:"postDeploy,omitempty"`
+}
+
+func (h *Hooks) Get(stage HookStage) Commands {
+	if h == nil {
+		return nil
+	}
+
+	switch stage {
+	case PreBuild:
+		return h.PreBuild
+	case PostBuild:
+		return h.PostBuild
+	case PreDeploy:
+		return h.PreDeploy
+	case PostDeploy:
+		return h.PostDeploy
+	}
+
+	return nil
+}
+
+// mergeHooks runs the root's hooks before the action's own hooks.
+func mergeHooks(base, override *Hooks) *Hooks {
+	if override == nil {
+		return base
+	}
+
+	if base == nil {
+		return override
+	}
+
+	return &Hooks{
+		PreBuild:   append(append(Commands{}, base.PreBuild...), override.PreBuild...),
+		PostBuild:  append(append(Commands{}, base.PostBuild...), override.PostBuild...),
+		PreDeploy:  append(append(Commands{}, base.PreDeploy...), override.PreDeploy...),
+		PostDeploy: append(append(Commands{}, base.PostDeploy...), override.PostDeploy...),
+	}
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Size is a number of bytes, configured either as a number or as a string such as "500KB" or "1.5MB".
+type Size int64
+
+var sizeUnits = []struct {
+	suffix     string
+	multiplier float64
+}{
+	{"GB", 1 << 30},
+	{"MB", 1 << 20},
+	{"KB", 1 << 10},
+	{"B", 1},
+}
+
+func ParseSize(value string) (Size, error) {
+	s := strings.ToUpper(strings.TrimSpace(value))
+	s = strings.Replace(s, "IB", "B", 1)
+
+	multiplier := float64(1)
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(s, unit.suffix) {
+			s = strings.TrimSpace(strings.TrimSuffix(s, unit.suffix))
+			multiplier = unit.multiplier
+
+			break
+		}
+	}
+
+	n, err := strconv.ParseFloat(s, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q, expected a number of bytes or a value such as 500KB", value)
+	}
+
+	return Size(n * multiplier), nil
+}
+
+func (s *Size) UnmarshalJSON(data []byte) error {
+	var n int64
+	if err := json.Unmarshal(data, &n); err == nil {
+		*s = Size(n)
+
+		return nil
+	}
+
+	var str string
+	if err := json.Unmarshal(data, &str); err != nil {
+		return fmt.Errorf("could not parse size: %s", data)
+	}
+
+	size, err := ParseSize(str)
+	if err != nil {
+		return err
+	}
+
+	*s = size
+
+	return nil
+}
+
+func (s Size) String() string {
+	return FormatBytes(int64(s))
+}
+
+func FormatBytes(n int64) string {
+	abs := n
+	if abs < 0 {
+		abs = -abs
+	}
+
+	for _, unit := range sizeUnits {
+		if unit.multiplier > 1 && float64(abs) >= unit.multiplier {
+			return fmt.Sprintf("%.2f%s", float64(n)/unit.multiplier, unit.suffix)
+		}
+	}
+
+	return fmt.Sprintf("%dB", n)
+}
@@ -2,6 +2,7 @@ package git
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
@@ -15,11 +16,26 @@ import (
 	"github.com/google/go-github/v48/github"
 
 	"github.com/gravitational/gamma/internal/action"
+	"github.com/gravitational/gamma/internal/size"
+	"github.com/gravitational/gamma/internal/version"
 )
 
 type Git interface {
 	GetChangedFiles() ([]string, error)
-	DeployAction(a action.Action) error
+	DeployAction(a action.Action) (*Deployment, error)
+	GetDeployedSizes(a action.Action) (size.Sizes, error)
+	GetDeployedFile(a action.Action, file string) ([]byte, error)
+	GetDeployedVersion(a action.Action) (*version.Version, error)
+}
+
+type Deployment struct {
+	Repository string
+	Ref        string
+	SHA        string
+	// Tag is the tag created for the action's version, which is empty if the version was already tagged
+	Tag string
+	// TagError is why the deployed commit could not be tagged, which leaves it deployed without a tag
+	TagError error
 }
 
 type git struct {
@@ -41,6 +57,21 @@ func New(wd string) (Git, error) {
 	return &git{repo, gh}, nil
 }
 
+// HeadCommit returns the hash of the HEAD commit of the repo at wd, without needing Github credentials.
+func HeadCommit(wd string) (string, error) {
+	repo, err := gogit.PlainOpen(wd)
+	if err != nil {
+		return "", fmt.Errorf("the current directory is not a git repo: %v", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("could not get HEAD: %v", err)
+	}
+
+	return head.Hash().String(), nil
+}
+
 func createGithubClient() (*github.Client, error) {
 	if os.Getenv("GITHUB_APP_PRIVATE_KEY") == "" {
 		return nil, errors.New("set your Github app's private key as GITHUB_APP_PRIVATE_KEY")
@@ -117,22 +148,183 @@ func (g *git) GetChangedFiles() ([]string, error) {
 	return files, nil
 }
 
-func (g *git) DeployAction(a action.Action) error {
+func (g *git) DeployAction(a action.Action) (*Deployment, error) {
 	ref, err := g.getRef(context.Background(), a)
 	if err != nil {
-		return fmt.Errorf("could not create git ref: %v", err)
+		return nil, fmt.Errorf("could not create git ref: %v", err)
 	}
 
 	tree, err := g.getTree(context.Background(), ref, a)
 	if err != nil {
-		return fmt.Errorf("could not create git tree: %v", err)
+		return nil, fmt.Errorf("could not create git tree: %v", err)
 	}
 
 	if err := g.pushCommit(context.Background(), ref, tree, a); err != nil {
-		return fmt.Errorf("could not push changes: %v", err)
+		return nil, fmt.Errorf("could not push changes: %v", err)
+	}
+
+	deployment := &Deployment{
+		Repository: fmt.Sprintf("%s/%s", a.Owner(), a.Name()),
+		Ref:        ref.GetRef(),
+		SHA:        ref.Object.GetSHA(),
+	}
+
+	if a.PackageInfo().Version == "" {
+		return deployment, nil
+	}
+
+	// the commit is already pushed, so a failing tag is reported along with the deployment
+	tag, err := g.createTag(context.Background(), a, "v"+a.PackageInfo().Version, deployment.SHA)
+	if err != nil {
+		deployment.TagError = fmt.Errorf("could not tag the deployed commit: %v", err)
+	}
+
+	deployment.Tag = tag
+
+	return deployment, nil
+}
+
+// createTag tags a commit in the action's repository, returning an empty tag if it already exists.
+func (g *git) createTag(ctx context.Context, a action.Action, tag, sha string) (string, error) {
+	_, resp, err := g.gh.Git.GetRef(ctx, a.Owner(), a.Name(), "refs/tags/"+tag)
+	if err == nil {
+		return "", nil
+	}
+	if resp == nil || resp.StatusCode != http.StatusNotFound {
+		return "", err
+	}
+
+	_, _, err = g.gh.Git.CreateRef(ctx, a.Owner(), a.Name(), &github.Reference{
+		Ref:    github.String("refs/tags/" + tag),
+		Object: &github.GitObject{SHA: github.String(sha)},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return tag, nil
+}
+
+func (g *git) GetDeployedSizes(a action.Action) (size.Sizes, error) {
+	ctx := context.Background()
+
+	ref, err := g.getRef(ctx, a)
+	if err != nil {
+		return nil, fmt.Errorf("could not get git ref: %v", err)
+	}
+
+	commit, _, err := g.gh.Git.GetCommit(ctx, a.Owner(), a.Name(), ref.Object.GetSHA())
+	if err != nil {
+		return nil, fmt.Errorf("could not get the deployed commit: %v", err)
+	}
+
+	tree, _, err := g.gh.Git.GetTree(ctx, a.Owner(), a.Name(), commit.Tree.GetSHA(), true)
+	if err != nil {
+		return nil, fmt.Errorf("could not get the deployed tree: %v", err)
+	}
+
+	if tree.GetTruncated() {
+		return nil, errors.New("the deployed tree is too large to compare")
+	}
+
+	sizes := make(size.Sizes)
+	for _, entry := range tree.Entries {
+		if entry.GetType() != "blob" {
+			continue
+		}
+
+		sizes[entry.GetPath()] = int64(entry.GetSize())
+	}
+
+	return sizes, nil
+}
+
+// GetDeployedFile returns the contents of a file in the action's repository, or nil if it does not exist.
+func (g *git) GetDeployedFile(a action.Action, file string) ([]byte, error) {
+	ctx := context.Background()
+
+	ref, err := g.getRef(ctx, a)
+	if err != nil {
+		return nil, fmt.Errorf("could not get git ref: %v", err)
+	}
+
+	contents, _, resp, err := g.gh.Repositories.GetContents(ctx, a.Owner(), a.Name(), file, &github.RepositoryContentGetOptions{
+		Ref: ref.GetRef(),
+	})
+	if resp != nil && resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not get the deployed %s: %v", file, err)
+	}
+
+	content, err := contents.GetContent()
+	if err != nil {
+		return nil, fmt.Errorf("could not decode the deployed %s: %v", file, err)
+	}
+
+	return []byte(content), nil
+}
+
+// GetDeployedVersion returns the highest version the action's repository is tagged with, falling back to
+// the version in the deployed package.json, or nil if neither exists.
+func (g *git) GetDeployedVersion(a action.Action) (*version.Version, error) {
+	ctx := context.Background()
+
+	var latest *version.Version
+
+	options := &github.ReferenceListOptions{
+		Ref:         "tags/v",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+
+	for {
+		refs, resp, err := g.gh.Git.ListMatchingRefs(ctx, a.Owner(), a.Name(), options)
+		if err != nil {
+			return nil, fmt.Errorf("could not list the deployed tags: %v", err)
+		}
+
+		for _, ref := range refs {
+			// tags that are not versions, such as v1 pointing at the latest 1.x.x, are skipped
+			v, err := version.Parse(strings.TrimPrefix(ref.GetRef(), "refs/tags/"))
+			if err != nil {
+				continue
+			}
+
+			if latest == nil || version.Compare(v, latest) > 0 {
+				latest = v
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+
+		options.Page = resp.NextPage
+	}
+
+	if latest != nil {
+		return latest, nil
+	}
+
+	contents, err := g.GetDeployedFile(a, "package.json")
+	if err != nil || contents == nil {
+		return nil, err
+	}
+
+	var deployed struct {
+		Version string `json:"version"`
+	}
+
+	if err := json.Unmarshal(contents, &deployed); err != nil {
+		return nil, fmt.Errorf("could not parse the deployed package.json: %v", err)
+	}
+
+	if deployed.Version == "" {
+		return nil, nil
 	}
 
-	return nil
+	return version.Parse(deployed.Version)
 }
 
 func (g *git) getTree(ctx context.Context, ref *github.Reference, a action.Action) (*github.Tree, error) {
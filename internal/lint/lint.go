@@ -0,0 +1,252 @@
+package lint
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gravitational/gamma/internal/schema"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+	SeverityOff     Severity = "off"
+)
+
+const (
+	// RuleUnusedInput reports inputs that are declared but never read by the action
+	RuleUnusedInput = "unused-input"
+	// RuleUndeclaredInput reports inputs the action reads that are not declared
+	RuleUndeclaredInput = "undeclared-input"
+	// RuleMissingDescription reports inputs and outputs without a description
+	RuleMissingDescription = "missing-description"
+	// RuleRequiredWithDefault reports required inputs that also have a default, which is never used
+	RuleRequiredWithDefault = "required-with-default"
+	// RuleInconsistentDescription reports inputs with the same name described differently across actions
+	RuleInconsistentDescription = "inconsistent-description"
+)
+
+// defaultRules are the rules and their severity when an action does not configure them.
+var defaultRules = map[string]Severity{
+	RuleUnusedInput:             SeverityWarning,
+	RuleUndeclaredInput:         SeverityError,
+	RuleMissingDescription:      SeverityError,
+	RuleRequiredWithDefault:     SeverityWarning,
+	RuleInconsistentDescription: SeverityWarning,
+}
+
+// Target is an action to lint.
+type Target struct {
+	Name string
+	// Directory is where the action's source is
+	Directory string
+	// GeneratedDirectory is where gamma codegen writes the action's modules, relative to Directory
+	GeneratedDirectory string
+	Config             *schema.Config
+	// Rules overrides the severity of rules for this action
+	Rules map[string]string
+}
+
+type Finding struct {
+	Action   string   `json:"action"`
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	File     string   `json:"file"`
+	Line     int      `json:"line"`
+	Message  string   `json:"message"`
+}
+
+// Run lints every target, returning the findings of the rules enabled for each.
+func Run(targets []*Target) ([]*Finding, error) {
+	var findings []*Finding
+
+	severities := make(map[string]map[string]Severity)
+
+	for _, target := range targets {
+		rules, err := resolveRules(target.Rules)
+		if err != nil {
+			return nil, fmt.Errorf("action %s: %v", target.Name, err)
+		}
+
+		severities[target.Name] = rules
+
+		l := &linter{target: target}
+
+		if err := l.lint(); err != nil {
+			return nil, fmt.Errorf("action %s: %v", target.Name, err)
+		}
+
+		findings = append(findings, l.findings...)
+	}
+
+	findings = append(findings, lintDescriptions(targets)...)
+
+	var enabled []*Finding
+
+	for _, finding := range findings {
+		severity := severities[finding.Action][finding.Rule]
+		if severity == SeverityOff {
+			continue
+		}
+
+		finding.Severity = severity
+		enabled = append(enabled, finding)
+	}
+
+	return enabled, nil
+}
+
+func resolveRules(configured map[string]string) (map[string]Severity, error) {
+	rules := make(map[string]Severity)
+	for rule, severity := range defaultRules {
+		rules[rule] = severity
+	}
+
+	for rule, severity := range configured {
+		if _, ok := defaultRules[rule]; !ok {
+			return nil, fmt.Errorf("unknown lint rule %s, expected one of %s", rule, strings.Join(ruleNames(), ", "))
+		}
+
+		switch Severity(severity) {
+		case SeverityError, SeverityWarning, SeverityOff:
+		default:
+			return nil, fmt.Errorf("unsupported severity %s for lint rule %s, expected error, warning or off", severity, rule)
+		}
+
+		rules[rule] = Severity(severity)
+	}
+
+	return rules, nil
+}
+
+func ruleNames() []string {
+	var names []string
+	for rule := range defaultRules {
+		names = append(names, rule)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+type linter struct {
+	target   *Target
+	findings []*Finding
+}
+
+func (l *linter) report(rule string, location schema.Location, format string, a ...any) {
+	l.findings = append(l.findings, &Finding{
+		Action:  l.target.Name,
+		Rule:    rule,
+		File:    location.File,
+		Line:    location.Line,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+func (l *linter) lint() error {
+	config := l.target.Config
+
+	for _, name := range config.InputNames() {
+		input := (*config.Inputs)[name]
+		location := config.InputLocation(name)
+
+		if strings.TrimSpace(input.Description) == "" {
+			l.report(RuleMissingDescription, location, "input %s has no description", name)
+		}
+
+		if input.Required != nil && *input.Required && input.Default != nil {
+			l.report(RuleRequiredWithDefault, location, "input %s is required, so its default is never used", name)
+		}
+	}
+
+	for _, name := range config.OutputNames() {
+		if strings.TrimSpace((*config.Outputs)[name].Description) == "" {
+			l.report(RuleMissingDescription, config.OutputLocation(name), "output %s has no description", name)
+		}
+	}
+
+	reads, err := readInputs(l.target.Directory, filepath.Join(l.target.Directory, l.target.GeneratedDirectory), config)
+	if err != nil {
+		return err
+	}
+
+	// docker actions receive every input as an environment variable, so how they are read is unknown
+	if reads == nil {
+		return nil
+	}
+
+	read := make(map[string]bool)
+
+	for _, r := range reads {
+		read[r.Name] = true
+
+		if config.Inputs == nil {
+			l.report(RuleUndeclaredInput, r.Location, "input %s is read, but not declared in action.yml", r.Name)
+
+			continue
+		}
+
+		if _, ok := (*config.Inputs)[r.Name]; !ok {
+			l.report(RuleUndeclaredInput, r.Location, "input %s is read, but not declared in action.yml", r.Name)
+		}
+	}
+
+	for _, name := range config.InputNames() {
+		if !read[name] {
+			l.report(RuleUnusedInput, config.InputLocation(name), "input %s is declared, but never read", name)
+		}
+	}
+
+	return nil
+}
+
+// lintDescriptions reports inputs that are described differently than an input of the same name in
+// an action before them.
+func lintDescriptions(targets []*Target) []*Finding {
+	var findings []*Finding
+
+	type description struct {
+		action string
+		value  string
+	}
+
+	seen := make(map[string]description)
+
+	for _, target := range targets {
+		config := target.Config
+
+		for _, name := range config.InputNames() {
+			value := strings.TrimSpace((*config.Inputs)[name].Description)
+			if value == "" {
+				continue
+			}
+
+			previous, ok := seen[name]
+			if !ok {
+				seen[name] = description{target.Name, value}
+
+				continue
+			}
+
+			if previous.value != value {
+				location := config.InputLocation(name)
+
+				findings = append(findings, &Finding{
+					Action:  target.Name,
+					Rule:    RuleInconsistentDescription,
+					File:    location.File,
+					Line:    location.Line,
+					Message: fmt.Sprintf("input %s is described differently than in action %s", name, previous.action),
+				})
+			}
+		}
+	}
+
+	return findings
+}
@@ -0,0 +1,161 @@
+package lint
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gravitational/gamma/internal/codegen"
+	"github.com/gravitational/gamma/internal/schema"
+	"github.com/gravitational/gamma/internal/utils"
+)
+
+var (
+	// getInputCall matches reading an input with @actions/core, such as core.getInput('version')
+	getInputCall = regexp.MustCompile("get(?:Boolean|Multiline)?Input\\(\\s*['\"`]([^'\"`]+)['\"`]")
+	// inputExpression matches using an input in a composite action, such as ${{ inputs.version }}, but
+	// not the inputs of other contexts, such as github.event.inputs.version
+	inputExpression = regexp.MustCompile(`(?:^|[^.\w-])inputs\.([a-zA-Z_][a-zA-Z0-9_-]*)`)
+	// generatedDestructuring matches destructuring the inputs from gamma codegen, such as
+	// const { version, dryRun } = getInputs()
+	generatedDestructuring = regexp.MustCompile(`\{([^{}]*)\}\s*=\s*getInputs\(\s*\)`)
+	// generatedAccess matches reading a property, such as inputs.dryRun
+	generatedAccess = regexp.MustCompile(`\.\s*([a-zA-Z_$][a-zA-Z0-9_$]*)`)
+
+	sourceExtensions = []string{".js", ".mjs", ".cjs", ".ts", ".mts", ".cts", ".jsx", ".tsx"}
+	skipDirectories  = []string{"node_modules", "dist", "build", "lib", ".git"}
+)
+
+// inputRead is a place an action reads an input.
+type inputRead struct {
+	Name     string
+	Location schema.Location
+}
+
+// readInputs finds every input an action reads, from its source code for Javascript actions and from
+// its steps for composite actions. Modules generated by gamma codegen read every input, so they are not
+// scanned, and the properties of their getInputs() used by the source count as reading those inputs
+// instead. It returns nil for Docker actions.
+func readInputs(directory, generated string, config *schema.Config) ([]*inputRead, error) {
+	if config.Runs.CompositeRun != nil {
+		return compositeInputs(config), nil
+	}
+
+	if config.Runs.JavascriptRun == nil {
+		return nil, nil
+	}
+
+	reads := []*inputRead{}
+
+	err := filepath.WalkDir(directory, func(file string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if file != directory && (utils.Contains(skipDirectories, d.Name()) || file == generated) {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !utils.Contains(sourceExtensions, path.Ext(file)) || strings.HasSuffix(file, ".d.ts") {
+			return nil
+		}
+
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+
+		if codegen.IsGenerated(contents) {
+			return nil
+		}
+
+		for i, line := range strings.Split(string(contents), "\n") {
+			for _, match := range getInputCall.FindAllStringSubmatch(line, -1) {
+				reads = append(reads, &inputRead{match[1], schema.Location{File: file, Line: i + 1}})
+			}
+		}
+
+		if strings.Contains(string(contents), "getInputs(") {
+			reads = append(reads, generatedInputs(file, string(contents), config)...)
+		}
+
+		return nil
+	})
+
+	return reads, err
+}
+
+// generatedInputs finds the inputs a file reads through the getInputs() of gamma codegen, by the
+// properties it destructures or accesses.
+func generatedInputs(file, contents string, config *schema.Config) []*inputRead {
+	inputs := make(map[string]string)
+	for _, name := range config.InputNames() {
+		inputs[codegen.Property(name)] = name
+	}
+
+	var reads []*inputRead
+
+	read := func(property string, offset int) {
+		if name, ok := inputs[property]; ok {
+			reads = append(reads, &inputRead{name, schema.Location{File: file, Line: strings.Count(contents[:offset], "\n") + 1}})
+		}
+	}
+
+	for _, match := range generatedDestructuring.FindAllStringSubmatchIndex(contents, -1) {
+		for _, entry := range strings.Split(contents[match[2]:match[3]], ",") {
+			entry = strings.TrimSpace(entry)
+
+			// the rest of the inputs could be read in any way, so they all count
+			if strings.HasPrefix(entry, "...") {
+				for _, name := range config.InputNames() {
+					read(codegen.Property(name), match[0])
+				}
+
+				continue
+			}
+
+			property, _, _ := strings.Cut(entry, ":")
+			property, _, _ = strings.Cut(property, "=")
+
+			read(strings.TrimSpace(property), match[0])
+		}
+	}
+
+	for _, match := range generatedAccess.FindAllStringSubmatchIndex(contents, -1) {
+		read(contents[match[2]:match[3]], match[0])
+	}
+
+	return reads
+}
+
+func compositeInputs(config *schema.Config) []*inputRead {
+	reads := []*inputRead{}
+
+	var walk func(node *yaml.Node)
+	walk = func(node *yaml.Node) {
+		if node.Kind == yaml.ScalarNode {
+			for _, match := range inputExpression.FindAllStringSubmatch(node.Value, -1) {
+				reads = append(reads, &inputRead{match[1], config.Location(node)})
+			}
+		}
+
+		for _, child := range node.Content {
+			walk(child)
+		}
+	}
+
+	if runs := config.Field("runs"); runs != nil {
+		walk(runs)
+	}
+
+	return reads
+}
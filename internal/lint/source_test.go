@@ -0,0 +1,141 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/gravitational/gamma/internal/codegen"
+	"github.com/gravitational/gamma/internal/schema"
+)
+
+func TestInputExpression(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{value: "${{ inputs.version }}", want: []string{"version"}},
+		{value: "echo ${{inputs.dry-run}} ${{ inputs.token }}", want: []string{"dry-run", "token"}},
+		{value: "inputs.version", want: []string{"version"}},
+		{value: "${{ github.event.inputs.version }}", want: nil},
+		{value: "${{ steps.setup.outputs.inputs.version }}", want: nil},
+		{value: "${{ my-inputs.version }}", want: nil},
+	}
+
+	for _, test := range tests {
+		var got []string
+		for _, match := range inputExpression.FindAllStringSubmatch(test.value, -1) {
+			got = append(got, match[1])
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("inputs in %q: got %v, want %v", test.value, got, test.want)
+		}
+	}
+}
+
+func TestReadInputsSkipsGeneratedModules(t *testing.T) {
+	dir := t.TempDir()
+
+	files := map[string]string{
+		"src/index.ts":            "const version = core.getInput('version');\n",
+		"src/generated/inputs.ts": "export const token = core.getInput('token');\n",
+		"src/inputs.ts":           "// Code generated by gamma from action.yml. DO NOT EDIT.\n\ncore.getInput('debug');\n",
+	}
+
+	for name, contents := range files {
+		filename := filepath.Join(dir, name)
+
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(filename, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	config, err := schema.ParseConfig("action.yml", []byte("name: action\ndescription: action\nruns:\n  using: node20\n  main: dist/index.js\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reads, err := readInputs(dir, filepath.Join(dir, "src/generated"), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(reads) != 1 || reads[0].Name != "version" || reads[0].Location.Line != 1 {
+		t.Errorf("expected only version to be read, got %+v", reads)
+	}
+}
+
+func TestGeneratedInputs(t *testing.T) {
+	dir := t.TempDir()
+
+	config, err := schema.ParseConfig(filepath.Join(dir, "action.yml"), []byte(`name: action
+description: action
+inputs:
+  version:
+    description: The version
+  dry-run:
+    description: Skip the upload
+  token:
+    description: The token
+runs:
+  using: node20
+  main: dist/index.js
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	module, err := codegen.Inputs(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"src/generated/inputs.ts": module,
+		"src/index.ts": `import { getInputs } from './generated/inputs';
+
+const { version } = getInputs();
+const inputs = getInputs();
+
+if (inputs.dryRun) {
+  console.log(version);
+}
+`,
+	}
+
+	for name, contents := range files {
+		filename := filepath.Join(dir, name)
+
+		if err := os.MkdirAll(filepath.Dir(filename), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := os.WriteFile(filename, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	findings, err := Run([]*Target{{
+		Name:               "action",
+		Directory:          dir,
+		GeneratedDirectory: "src/generated",
+		Config:             config,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(findings) != 1 || findings[0].Rule != RuleUnusedInput || findings[0].Message != "input token is declared, but never read" {
+		for _, finding := range findings {
+			t.Errorf("unexpected finding: %s %s", finding.Rule, finding.Message)
+		}
+
+		t.Fatalf("expected only token to be unused")
+	}
+}
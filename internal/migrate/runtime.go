@@ -0,0 +1,175 @@
+package migrate
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	nodeRuntime = regexp.MustCompile(`^node(\d+)$`)
+	enginesNode = regexp.MustCompile(`("engines"\s*:\s*\{[^}]*?"node"\s*:\s*")([^"]*)(")`)
+)
+
+type Change struct {
+	File string
+	Line int
+	From string
+	To   string
+}
+
+// ParseRuntime returns the major node version of a runs.using value such as node20.
+func ParseRuntime(runtime string) (int, error) {
+	match := nodeRuntime.FindStringSubmatch(runtime)
+	if match == nil {
+		return 0, fmt.Errorf("invalid node runtime: %s, expected a value such as node20", runtime)
+	}
+
+	return strconv.Atoi(match[1])
+}
+
+// Runtime rewrites runs.using in a YAML file to the target runtime, editing only the value itself
+// so comments, formatting and key order are kept. If from is set, only that runtime is rewritten.
+// It returns nil if the file does not define a node runtime that needs changing.
+func Runtime(filename, from, to string, dryRun bool) (*Change, error) {
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", filename, err)
+	}
+
+	var document yaml.Node
+	if err := yaml.Unmarshal(contents, &document); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", filename, err)
+	}
+
+	using := findUsing(&document)
+	if using == nil || !nodeRuntime.MatchString(using.Value) || using.Value == to {
+		return nil, nil
+	}
+
+	if from != "" && using.Value != from {
+		return nil, nil
+	}
+
+	lines := strings.Split(string(contents), "\n")
+	line := []rune(lines[using.Line-1])
+
+	start := using.Column - 1
+	offset := strings.Index(string(line[start:]), using.Value)
+	if offset == -1 {
+		return nil, fmt.Errorf("could not find runs.using at %s:%d", filename, using.Line)
+	}
+
+	prefix := string(line[:start]) + string(line[start:])[:offset]
+	suffix := string(line[start:])[offset+len(using.Value):]
+	lines[using.Line-1] = prefix + to + suffix
+
+	change := &Change{
+		File: filename,
+		Line: using.Line,
+		From: using.Value,
+		To:   to,
+	}
+
+	if dryRun {
+		return change, nil
+	}
+
+	if err := writeFile(filename, []byte(strings.Join(lines, "\n"))); err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+// EnginesNode rewrites engines.node in a package.json to require the given major version,
+// leaving the rest of the file untouched. It returns nil if engines.node is not set or already matches.
+func EnginesNode(filename string, major int, dryRun bool) (*Change, error) {
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading %s: %v", filename, err)
+	}
+
+	match := enginesNode.FindSubmatchIndex(contents)
+	if match == nil {
+		return nil, nil
+	}
+
+	previous := string(contents[match[4]:match[5]])
+	next := fmt.Sprintf(">=%d", major)
+
+	if previous == next {
+		return nil, nil
+	}
+
+	change := &Change{
+		File: filename,
+		Line: strings.Count(string(contents[:match[4]]), "\n") + 1,
+		From: previous,
+		To:   next,
+	}
+
+	if dryRun {
+		return change, nil
+	}
+
+	var updated []byte
+	updated = append(updated, contents[:match[4]]...)
+	updated = append(updated, next...)
+	updated = append(updated, contents[match[5]:]...)
+
+	if err := writeFile(filename, updated); err != nil {
+		return nil, err
+	}
+
+	return change, nil
+}
+
+func findUsing(document *yaml.Node) *yaml.Node {
+	if document.Kind != yaml.DocumentNode || len(document.Content) == 0 {
+		return nil
+	}
+
+	runs := mappingValue(document.Content[0], "runs")
+	if runs == nil {
+		return nil
+	}
+
+	using := mappingValue(runs, "using")
+	if using == nil || using.Kind != yaml.ScalarNode {
+		return nil
+	}
+
+	return using
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func writeFile(filename string, contents []byte) error {
+	info, err := os.Stat(filename)
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filename, contents, info.Mode().Perm()); err != nil {
+		return fmt.Errorf("could not write %s: %v", filename, err)
+	}
+
+	return nil
+}
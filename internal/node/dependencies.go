@@ -0,0 +1,188 @@
+package node
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+// License is the license of a package, which package.json allows to be either an SPDX expression
+// or an object with a type.
+type License struct {
+	Value string
+}
+
+func (l *License) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err == nil {
+		l.Value = value
+
+		return nil
+	}
+
+	var obj struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &obj); err == nil {
+		l.Value = obj.Type
+	}
+
+	return nil
+}
+
+// SPDX returns the SPDX expression of the package's license, falling back to the deprecated licenses array.
+func (p *PackageInfo) SPDX() string {
+	if p.License.Value != "" {
+		return p.License.Value
+	}
+
+	var licenses []string
+	for _, license := range p.Licenses {
+		if license.Value != "" {
+			licenses = append(licenses, license.Value)
+		}
+	}
+
+	if len(licenses) == 0 {
+		return ""
+	}
+
+	if len(licenses) == 1 {
+		return licenses[0]
+	}
+
+	return "(" + strings.Join(licenses, " OR ") + ")"
+}
+
+// GetDependencies resolves the production dependency tree of a package from node_modules,
+// following Node's module resolution up to the root of the monorepo.
+func (s *packageService) GetDependencies(p *PackageInfo) ([]*PackageInfo, error) {
+	seen := make(map[string]*PackageInfo)
+	queue := []*PackageInfo{p}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, dependency := range current.dependencies() {
+			filename, ok := s.resolve(current.Path, dependency.name)
+			if !ok {
+				if dependency.optional {
+					continue
+				}
+
+				return nil, fmt.Errorf("could not resolve dependency %s of %s, have you run yarn install?", dependency.name, current.Name)
+			}
+
+			if _, ok := seen[filename]; ok {
+				continue
+			}
+
+			d, err := s.readDependency(filename)
+			if err != nil {
+				return nil, err
+			}
+
+			seen[filename] = d
+			queue = append(queue, d)
+		}
+	}
+
+	var dependencies []*PackageInfo
+	for _, d := range seen {
+		dependencies = append(dependencies, d)
+	}
+
+	sort.Slice(dependencies, func(i, j int) bool {
+		if dependencies[i].Name == dependencies[j].Name {
+			return dependencies[i].Version < dependencies[j].Version
+		}
+
+		return dependencies[i].Name < dependencies[j].Name
+	})
+
+	return dependencies, nil
+}
+
+// readDependency reads only the fields needed to walk the dependency tree, as third-party
+// packages use shapes for fields such as repository that PackageInfo does not accept.
+func (s *packageService) readDependency(filename string) (*PackageInfo, error) {
+	var obj struct {
+		Name                 string            `json:"name"`
+		Version              string            `json:"version"`
+		Private              bool              `json:"private"`
+		License              License           `json:"license"`
+		Licenses             []License         `json:"licenses"`
+		Dependencies         map[string]string `json:"dependencies"`
+		OptionalDependencies map[string]string `json:"optionalDependencies"`
+	}
+
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("error reading package.json: %v [%s]", err, filename)
+	}
+
+	if err := json.Unmarshal(contents, &obj); err != nil {
+		return nil, fmt.Errorf("error parsing package.json: %v [%s]", err, filename)
+	}
+
+	return &PackageInfo{
+		Name:                 obj.Name,
+		Version:              obj.Version,
+		Private:              obj.Private,
+		License:              obj.License,
+		Licenses:             obj.Licenses,
+		Dependencies:         obj.Dependencies,
+		OptionalDependencies: obj.OptionalDependencies,
+		Path:                 path.Dir(filename),
+		RootPath:             s.RootPath,
+	}, nil
+}
+
+type dependency struct {
+	name     string
+	optional bool
+}
+
+func (p *PackageInfo) dependencies() []dependency {
+	var dependencies []dependency
+
+	for name := range p.Dependencies {
+		dependencies = append(dependencies, dependency{name, false})
+	}
+
+	for name := range p.OptionalDependencies {
+		if _, ok := p.Dependencies[name]; !ok {
+			dependencies = append(dependencies, dependency{name, true})
+		}
+	}
+
+	sort.Slice(dependencies, func(i, j int) bool {
+		return dependencies[i].name < dependencies[j].name
+	})
+
+	return dependencies
+}
+
+func (s *packageService) resolve(from, name string) (string, bool) {
+	dir := from
+
+	for {
+		if path.Base(dir) != "node_modules" {
+			filename := path.Join(dir, "node_modules", name, "package.json")
+
+			if _, err := os.Stat(filename); err == nil {
+				return filename, true
+			}
+		}
+
+		if dir == s.RootPath || dir == "/" || dir == "." {
+			return "", false
+		}
+
+		dir = path.Dir(dir)
+	}
+}
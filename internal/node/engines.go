@@ -0,0 +1,243 @@
+package node
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// version is a node version without its prerelease, compared part by part.
+type version [3]int
+
+func (v version) less(other version) bool {
+	for i := range v {
+		if v[i] != other[i] {
+			return v[i] < other[i]
+		}
+	}
+
+	return false
+}
+
+// interval is the versions from lower to upper, where upper is excluded unless includeUpper is set.
+type interval struct {
+	lower        version
+	upper        version
+	excludeLower bool
+	includeUpper bool
+	// unbounded is set when there is no upper bound
+	unbounded bool
+}
+
+func (i interval) empty() bool {
+	if i.unbounded {
+		return false
+	}
+
+	if i.lower == i.upper {
+		return i.excludeLower || !i.includeUpper
+	}
+
+	return i.upper.less(i.lower)
+}
+
+func (i interval) intersect(other interval) interval {
+	result := i
+
+	if i.lower.less(other.lower) || (i.lower == other.lower && other.excludeLower) {
+		result.lower, result.excludeLower = other.lower, other.excludeLower
+	}
+
+	switch {
+	case other.unbounded:
+	case i.unbounded, other.upper.less(i.upper), i.upper == other.upper && !other.includeUpper:
+		result.upper, result.includeUpper, result.unbounded = other.upper, other.includeUpper, false
+	}
+
+	return result
+}
+
+// versionRange is a semver range such as >=18, ^18 || ^20 or 18.x, as used by engines.node. Each
+// alternative is the intersection of its comparators.
+type versionRange []interval
+
+func (r versionRange) intersects(other interval) bool {
+	for _, alternative := range r {
+		if !alternative.intersect(other).empty() {
+			return true
+		}
+	}
+
+	return false
+}
+
+func parseRange(value string) (versionRange, error) {
+	var r versionRange
+
+	for _, alternative := range strings.Split(value, "||") {
+		fields := strings.Fields(alternative)
+
+		// a hyphen range, such as 16 - 20, includes both ends
+		if len(fields) == 3 && fields[1] == "-" {
+			lower, err := parseComparator(">=" + fields[0])
+			if err != nil {
+				return nil, err
+			}
+
+			upper, err := parseComparator("<=" + fields[2])
+			if err != nil {
+				return nil, err
+			}
+
+			r = append(r, lower.intersect(upper))
+
+			continue
+		}
+
+		i := interval{unbounded: true}
+
+		for j := 0; j < len(fields); j++ {
+			comparator := fields[j]
+
+			// operators can be separated from their version, such as >= 18
+			if strings.Trim(comparator, "<>=^~") == "" && j+1 < len(fields) {
+				j++
+				comparator += fields[j]
+			}
+
+			c, err := parseComparator(comparator)
+			if err != nil {
+				return nil, err
+			}
+
+			i = i.intersect(c)
+		}
+
+		r = append(r, i)
+	}
+
+	return r, nil
+}
+
+func parseComparator(value string) (interval, error) {
+	operator := value[:len(value)-len(strings.TrimLeft(value, "<>=^~"))]
+
+	v, parts, err := parsePartial(strings.TrimPrefix(strings.TrimPrefix(value, operator), "v"))
+	if err != nil {
+		return interval{}, err
+	}
+
+	// next is the first version after every version matching the given parts
+	next := v
+	if parts > 0 {
+		next[parts-1]++
+		for i := parts; i < 3; i++ {
+			next[i] = 0
+		}
+	}
+
+	all := interval{unbounded: true}
+	none := interval{lower: version{1}, upper: version{0}}
+
+	switch operator {
+	case "", "=":
+		if parts == 0 {
+			return all, nil
+		}
+
+		if parts == 3 {
+			return interval{lower: v, upper: v, includeUpper: true}, nil
+		}
+
+		return interval{lower: v, upper: next}, nil
+	case ">=":
+		return interval{lower: v, unbounded: true}, nil
+	case ">":
+		if parts == 0 {
+			return none, nil
+		}
+
+		if parts == 3 {
+			return interval{lower: v, excludeLower: true, unbounded: true}, nil
+		}
+
+		return interval{lower: next, unbounded: true}, nil
+	case "<":
+		if parts == 0 {
+			return none, nil
+		}
+
+		return interval{upper: v}, nil
+	case "<=":
+		if parts == 0 {
+			return all, nil
+		}
+
+		if parts == 3 {
+			return interval{upper: v, includeUpper: true}, nil
+		}
+
+		return interval{upper: next}, nil
+	case "~":
+		if parts == 0 {
+			return all, nil
+		}
+
+		upper := version{v[0] + 1}
+		if parts > 1 {
+			upper = version{v[0], v[1] + 1}
+		}
+
+		return interval{lower: v, upper: upper}, nil
+	case "^":
+		if parts == 0 {
+			return all, nil
+		}
+
+		// the first non-zero part given can not change
+		for i := 0; i < parts; i++ {
+			if v[i] != 0 || i == parts-1 {
+				upper := version{}
+				copy(upper[:i], v[:i])
+				upper[i] = v[i] + 1
+
+				return interval{lower: v, upper: upper}, nil
+			}
+		}
+	}
+
+	return interval{}, fmt.Errorf("unsupported operator %s in %s", operator, value)
+}
+
+// parsePartial parses a version that may leave out parts or use x or * for them, returning how many
+// parts it specifies.
+func parsePartial(value string) (version, int, error) {
+	var v version
+
+	value, _, _ = strings.Cut(value, "+")
+	value, _, _ = strings.Cut(value, "-")
+
+	if value == "" {
+		return v, 0, nil
+	}
+
+	parts := strings.Split(value, ".")
+	if len(parts) > 3 {
+		return v, 0, fmt.Errorf("invalid version %s", value)
+	}
+
+	for i, part := range parts {
+		if part == "x" || part == "X" || part == "*" {
+			return v, i, nil
+		}
+
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return v, 0, fmt.Errorf("invalid version %s", value)
+		}
+
+		v[i] = n
+	}
+
+	return v, len(parts), nil
+}
@@ -0,0 +1,50 @@
+package node
+
+import "testing"
+
+func TestAllowsNodeMajor(t *testing.T) {
+	tests := []struct {
+		engines string
+		major   int
+		allowed bool
+		ok      bool
+	}{
+		{"20", 20, true, true},
+		{"20", 18, false, true},
+		{">=18", 20, true, true},
+		{">=18", 16, false, true},
+		{">= 18", 20, true, true},
+		{">18", 18, false, true},
+		{">18.0.0", 18, true, true},
+		{">18", 20, true, true},
+		{"<20", 20, false, true},
+		{"<=20", 20, true, true},
+		{"^18 || ^20", 20, true, true},
+		{"^18 || ^20", 16, false, true},
+		{"^18.12.0", 18, true, true},
+		{"^18.12.0", 20, false, true},
+		{"~20.1", 20, true, true},
+		{"18.x", 18, true, true},
+		{"18.x", 20, false, true},
+		{"*", 20, true, true},
+		{">=16 <20", 20, false, true},
+		{">=16 <20", 18, true, true},
+		{"16 - 20", 20, true, true},
+		{"16 - 20", 22, false, true},
+		{"v20.0.0", 20, true, true},
+		{"not a range", 20, false, false},
+	}
+
+	for _, test := range tests {
+		p := &PackageInfo{Engines: map[string]string{"node": test.engines}}
+
+		allowed, ok := p.AllowsNodeMajor(test.major)
+		if allowed != test.allowed || ok != test.ok {
+			t.Errorf("AllowsNodeMajor(%d) with engines.node %q = %t, %t, want %t, %t", test.major, test.engines, allowed, ok, test.allowed, test.ok)
+		}
+	}
+
+	if _, ok := (&PackageInfo{}).AllowsNodeMajor(20); ok {
+		t.Error("AllowsNodeMajor without engines.node should not be ok")
+	}
+}
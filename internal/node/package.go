@@ -7,6 +7,8 @@ import (
 	"io/fs"
 	"os"
 	"path"
+
+	"github.com/gravitational/gamma/internal/config"
 )
 
 type Workspaces struct {
@@ -40,6 +42,7 @@ type packageService struct {
 type PackageService interface {
 	ReadPackageInfo(filename string) (*PackageInfo, error)
 	GetWorkspaces(p *PackageInfo) ([]*PackageInfo, error)
+	GetDependencies(p *PackageInfo) ([]*PackageInfo, error)
 }
 
 func NewPackageService(rootPath string) PackageService {
@@ -67,10 +70,18 @@ func (s *packageService) ReadPackageInfo(filename string) (*PackageInfo, error)
 }
 
 type PackageInfo struct {
-	Name       string     `json:"name"`
-	Version    string     `json:"version"`
-	Repository string     `json:"repository"`
-	Workspaces Workspaces `json:"workspaces"`
+	Name                 string            `json:"name"`
+	Version              string            `json:"version"`
+	Private              bool              `json:"private"`
+	Repository           string            `json:"repository"`
+	License              License           `json:"license"`
+	Licenses             []License         `json:"licenses"`
+	Dependencies         map[string]string `json:"dependencies"`
+	OptionalDependencies map[string]string `json:"optionalDependencies"`
+	Engines              map[string]string `json:"engines"`
+	Scripts              map[string]string `json:"scripts"`
+	Workspaces           Workspaces        `json:"workspaces"`
+	Gamma                *config.Config    `json:"gamma"`
 
 	Path     string
 	RootPath string
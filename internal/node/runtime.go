@@ -0,0 +1,143 @@
+package node
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+)
+
+var (
+	nccTarget = regexp.MustCompile(`ncc\s.*--target[=\s]+(\S+)`)
+)
+
+// AllowsNodeMajor returns whether engines.node allows any version of the given node major, and false
+// for ok if engines.node is not set or could not be parsed.
+func (p *PackageInfo) AllowsNodeMajor(major int) (allowed bool, ok bool) {
+	constraint, ok := p.Engines["node"]
+	if !ok {
+		return false, false
+	}
+
+	r, err := parseRange(constraint)
+	if err != nil {
+		return false, false
+	}
+
+	return r.intersects(interval{
+		lower: version{major, 0, 0},
+		upper: version{major + 1, 0, 0},
+	}), true
+}
+
+// NCCTarget returns the ECMAScript target passed to ncc in the build script, if any.
+func (p *PackageInfo) NCCTarget() string {
+	match := nccTarget.FindStringSubmatch(p.Scripts["build"])
+	if match == nil {
+		return ""
+	}
+
+	return match[1]
+}
+
+// TypeScriptTarget returns compilerOptions.target from the package's tsconfig.json, following
+// relative extends.
+func (p *PackageInfo) TypeScriptTarget() (string, error) {
+	return readTypeScriptTarget(path.Join(p.Path, "tsconfig.json"), 0)
+}
+
+func readTypeScriptTarget(filename string, depth int) (string, error) {
+	if depth > 10 {
+		return "", fmt.Errorf("too many nested extends in %s", filename)
+	}
+
+	contents, err := os.ReadFile(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("error reading %s: %v", filename, err)
+	}
+
+	var tsconfig struct {
+		Extends         string `json:"extends"`
+		CompilerOptions struct {
+			Target string `json:"target"`
+		} `json:"compilerOptions"`
+	}
+
+	if err := json.Unmarshal(stripJSONComments(contents), &tsconfig); err != nil {
+		return "", fmt.Errorf("error parsing %s: %v", filename, err)
+	}
+
+	if tsconfig.CompilerOptions.Target != "" {
+		return tsconfig.CompilerOptions.Target, nil
+	}
+
+	if strings.HasPrefix(tsconfig.Extends, ".") {
+		extends := path.Join(path.Dir(filename), tsconfig.Extends)
+		if path.Ext(extends) != ".json" {
+			extends += ".json"
+		}
+
+		return readTypeScriptTarget(extends, depth+1)
+	}
+
+	return "", nil
+}
+
+// stripJSONComments removes comments and trailing commas, which tsconfig.json allows.
+func stripJSONComments(data []byte) []byte {
+	var out []byte
+
+	inString := false
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		if inString {
+			out = append(out, c)
+
+			if c == '\\' && i+1 < len(data) {
+				out = append(out, data[i+1])
+				i++
+			} else if c == '"' {
+				inString = false
+			}
+
+			continue
+		}
+
+		switch {
+		case c == '"':
+			inString = true
+			out = append(out, c)
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for i < len(data) && data[i] != '\n' {
+				i++
+			}
+			out = append(out, '\n')
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			i += 2
+			for i+1 < len(data) && !(data[i] == '*' && data[i+1] == '/') {
+				i++
+			}
+			i++
+		case c == '}' || c == ']':
+			j := len(out) - 1
+			for j >= 0 && strings.ContainsRune(" \t\r\n", rune(out[j])) {
+				j--
+			}
+			if j >= 0 && out[j] == ',' {
+				out = append(out[:j], out[j+1:]...)
+			}
+			out = append(out, c)
+		default:
+			out = append(out, c)
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,122 @@
+package release
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gravitational/gamma/internal/version"
+)
+
+// Directory is where intents are kept, relative to the root of the monorepo.
+const Directory = ".gamma/changes"
+
+var unsafeCharacters = regexp.MustCompile(`[^a-z0-9]+`)
+
+// Intent records that an action needs a release, and what changed in it.
+type Intent struct {
+	File    string       `yaml:"-"`
+	Action  string       `yaml:"action"`
+	Bump    version.Bump `yaml:"bump"`
+	Summary string       `yaml:"summary"`
+}
+
+func (i *Intent) validate() error {
+	if i.Action == "" {
+		return errors.New("missing action")
+	}
+
+	if _, err := version.ParseBump(string(i.Bump)); err != nil {
+		return err
+	}
+
+	if strings.TrimSpace(i.Summary) == "" {
+		return errors.New("missing summary")
+	}
+
+	return nil
+}
+
+// WriteIntent writes an intent to a new file, named after its action with a random suffix so intents
+// from different branches do not conflict.
+func WriteIntent(root string, intent *Intent) (string, error) {
+	if err := intent.validate(); err != nil {
+		return "", err
+	}
+
+	suffix := make([]byte, 4)
+	if _, err := rand.Read(suffix); err != nil {
+		return "", fmt.Errorf("could not generate a file name: %v", err)
+	}
+
+	name := strings.Trim(unsafeCharacters.ReplaceAllString(strings.ToLower(intent.Action), "-"), "-")
+	filename := path.Join(root, Directory, fmt.Sprintf("%s-%s.yml", name, hex.EncodeToString(suffix)))
+
+	contents, err := yaml.Marshal(intent)
+	if err != nil {
+		return "", err
+	}
+
+	if err := os.MkdirAll(path.Dir(filename), 0755); err != nil {
+		return "", fmt.Errorf("could not create %s: %v", path.Dir(filename), err)
+	}
+
+	if err := os.WriteFile(filename, contents, 0644); err != nil {
+		return "", fmt.Errorf("could not write %s: %v", filename, err)
+	}
+
+	intent.File = filename
+
+	return filename, nil
+}
+
+// ReadIntents returns the pending intents in the monorepo, ordered by file name.
+func ReadIntents(root string) ([]*Intent, error) {
+	dir := path.Join(root, Directory)
+
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %v", dir, err)
+	}
+
+	var intents []*Intent
+
+	for _, entry := range entries {
+		if entry.IsDir() || (path.Ext(entry.Name()) != ".yml" && path.Ext(entry.Name()) != ".yaml") {
+			continue
+		}
+
+		filename := path.Join(dir, entry.Name())
+
+		contents, err := os.ReadFile(filename)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %v", filename, err)
+		}
+
+		var intent Intent
+		if err := yaml.Unmarshal(contents, &intent); err != nil {
+			return nil, fmt.Errorf("error parsing %s: %v", filename, err)
+		}
+
+		if err := intent.validate(); err != nil {
+			return nil, fmt.Errorf("invalid intent %s: %v", filename, err)
+		}
+
+		intent.File = filename
+
+		intents = append(intents, &intent)
+	}
+
+	return intents, nil
+}
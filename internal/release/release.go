@@ -0,0 +1,262 @@
+package release
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/gravitational/gamma/internal/node"
+	"github.com/gravitational/gamma/internal/version"
+)
+
+var headings = map[version.Bump]string{
+	version.Major: "Major changes",
+	version.Minor: "Minor changes",
+	version.Patch: "Patch changes",
+}
+
+// Release is the next version of an action, and the intents that make it up.
+type Release struct {
+	Package  *node.PackageInfo
+	Previous *version.Version
+	Next     *version.Version
+	Intents  []*Intent
+
+	files []*renderedFile
+}
+
+// renderedFile is a file a release writes when it is applied.
+type renderedFile struct {
+	name     string
+	contents []byte
+	perm     fs.FileMode
+}
+
+// Plan groups intents by action, bumping each action's version by the largest bump of its intents.
+func Plan(packages []*node.PackageInfo, intents []*Intent) ([]*Release, error) {
+	releases := make(map[string]*Release)
+
+	for _, intent := range intents {
+		r, ok := releases[intent.Action]
+		if !ok {
+			p := findPackage(packages, intent.Action)
+			if p == nil {
+				return nil, fmt.Errorf("intent %s is for action %s, which does not exist", intent.File, intent.Action)
+			}
+
+			previous, err := version.Parse(p.Version)
+			if err != nil {
+				return nil, fmt.Errorf("action %s: %v", p.Name, err)
+			}
+
+			r = &Release{Package: p, Previous: previous}
+			releases[intent.Action] = r
+		}
+
+		r.Intents = append(r.Intents, intent)
+	}
+
+	var planned []*Release
+
+	for _, r := range releases {
+		bump := version.Patch
+		for _, intent := range r.Intents {
+			bump = version.Greater(bump, intent.Bump)
+		}
+
+		r.Next = r.Previous.Bump(bump)
+
+		planned = append(planned, r)
+	}
+
+	sort.Slice(planned, func(i, j int) bool {
+		return planned[i].Package.Name < planned[j].Package.Name
+	})
+
+	return planned, nil
+}
+
+func findPackage(packages []*node.PackageInfo, name string) *node.PackageInfo {
+	for _, p := range packages {
+		if p.Name == name {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// Render prepares the release's package.json, with the next version, and CHANGELOG.md, with its changes
+// at the top, without writing them, so every release can fail before any of them is applied.
+func (r *Release) Render() error {
+	packageFile := path.Join(r.Package.Path, "package.json")
+
+	packageJSON, err := renderPackageVersion(packageFile, r.Next.String())
+	if err != nil {
+		return err
+	}
+
+	changelogFile := path.Join(r.Package.Path, "CHANGELOG.md")
+
+	changelog, err := renderChangelog(changelogFile, r.Changelog())
+	if err != nil {
+		return err
+	}
+
+	r.files = []*renderedFile{packageJSON, changelog}
+
+	return nil
+}
+
+// Apply writes the files rendered for the release.
+func (r *Release) Apply() error {
+	if r.files == nil {
+		return fmt.Errorf("the release of %s is not rendered", r.Package.Name)
+	}
+
+	for _, f := range r.files {
+		if err := os.WriteFile(f.name, f.contents, f.perm); err != nil {
+			return fmt.Errorf("could not write %s: %v", f.name, err)
+		}
+	}
+
+	return nil
+}
+
+// Changelog returns the release's section of the changelog, with its changes grouped by bump.
+func (r *Release) Changelog() string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "## %s\n", r.Next)
+
+	for _, bump := range []version.Bump{version.Major, version.Minor, version.Patch} {
+		var entries []string
+
+		for _, intent := range r.Intents {
+			if intent.Bump == bump {
+				entries = append(entries, "- "+strings.ReplaceAll(strings.TrimSpace(intent.Summary), "\n", "\n  "))
+			}
+		}
+
+		if len(entries) == 0 {
+			continue
+		}
+
+		fmt.Fprintf(&b, "\n### %s\n\n%s\n", headings[bump], strings.Join(entries, "\n"))
+	}
+
+	return b.String()
+}
+
+// Consume deletes the intent files of the release, once it is applied.
+func (r *Release) Consume() error {
+	for _, intent := range r.Intents {
+		if err := os.Remove(intent.File); err != nil {
+			return fmt.Errorf("could not delete %s: %v", intent.File, err)
+		}
+	}
+
+	return nil
+}
+
+// renderPackageVersion rewrites the top-level version of a package.json, leaving the rest of the file untouched.
+func renderPackageVersion(filename, next string) (*renderedFile, error) {
+	contents, perm, err := readFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	start, end, err := findVersion(contents)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", filename, err)
+	}
+
+	value, err := json.Marshal(next)
+	if err != nil {
+		return nil, err
+	}
+
+	var updated []byte
+	updated = append(updated, contents[:start]...)
+	updated = append(updated, value...)
+	updated = append(updated, contents[end:]...)
+
+	return &renderedFile{filename, updated, perm}, nil
+}
+
+// findVersion returns the offsets of the top-level version value in a package.json.
+func findVersion(contents []byte) (int, int, error) {
+	decoder := json.NewDecoder(bytes.NewReader(contents))
+
+	if token, err := decoder.Token(); err != nil || token != json.Delim('{') {
+		return 0, 0, errors.New("expected an object")
+	}
+
+	for decoder.More() {
+		key, err := decoder.Token()
+		if err != nil {
+			return 0, 0, err
+		}
+
+		var value json.RawMessage
+		if err := decoder.Decode(&value); err != nil {
+			return 0, 0, err
+		}
+
+		if key != "version" {
+			continue
+		}
+
+		end := int(decoder.InputOffset())
+
+		return end - len(value), end, nil
+	}
+
+	return 0, 0, errors.New("missing the version field")
+}
+
+// renderChangelog adds a section below the title of a changelog, creating it if needed.
+func renderChangelog(filename, section string) (*renderedFile, error) {
+	contents, perm, err := readFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return &renderedFile{filename, []byte("# Changelog\n\n" + section), 0644}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	existing := string(contents)
+
+	if !strings.HasPrefix(existing, "# ") {
+		return &renderedFile{filename, []byte(section + "\n" + existing), perm}, nil
+	}
+
+	title, rest, _ := strings.Cut(existing, "\n")
+
+	return &renderedFile{filename, []byte(title + "\n\n" + section + "\n" + strings.TrimLeft(rest, "\n")), perm}, nil
+}
+
+// readFile returns the contents of a file along with its permissions, to write it back with the same.
+func readFile(filename string) ([]byte, fs.FileMode, error) {
+	info, err := os.Stat(filename)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, 0, err
+		}
+
+		return nil, 0, fmt.Errorf("error reading %s: %v", filename, err)
+	}
+
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, 0, fmt.Errorf("error reading %s: %v", filename, err)
+	}
+
+	return contents, info.Mode().Perm(), nil
+}
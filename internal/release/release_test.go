@@ -0,0 +1,313 @@
+package release
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/gravitational/gamma/internal/node"
+	"github.com/gravitational/gamma/internal/version"
+)
+
+func TestFindVersion(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		want     string
+		err      string
+	}{
+		{
+			name:     "top-level version",
+			contents: `{"name":"a","version":"1.2.3"}`,
+			want:     `"1.2.3"`,
+		},
+		{
+			name: "nested version before the top-level one",
+			contents: `{
+  "name": "a",
+  "engines": { "version": "0.0.1" },
+  "dependencies": { "version": "^2.0.0" },
+  "version": "1.2.3",
+  "private": true
+}`,
+			want: `"1.2.3"`,
+		},
+		{
+			name:     "spaces around the value",
+			contents: "{\"version\" :  \"1.2.3\"  }",
+			want:     `"1.2.3"`,
+		},
+		{
+			name:     "only nested versions",
+			contents: `{"name":"a","engines":{"version":"1.2.3"}}`,
+			err:      "missing the version field",
+		},
+		{
+			name:     "not an object",
+			contents: `["version"]`,
+			err:      "expected an object",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			start, end, err := findVersion([]byte(test.contents))
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected an error containing %q, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := test.contents[start:end]; got != test.want {
+				t.Errorf("got %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRenderChangelog(t *testing.T) {
+	section := "## 1.1.0\n\n### Minor changes\n\n- add x\n"
+
+	tests := []struct {
+		name     string
+		existing *string
+		want     string
+	}{
+		{
+			name: "new changelog",
+			want: "# Changelog\n\n" + section,
+		},
+		{
+			name:     "below the title",
+			existing: stringPointer("# Changelog\n\n## 1.0.0\n\n- first\n"),
+			want:     "# Changelog\n\n" + section + "\n## 1.0.0\n\n- first\n",
+		},
+		{
+			name:     "without a title",
+			existing: stringPointer("## 1.0.0\n\n- first\n"),
+			want:     section + "\n## 1.0.0\n\n- first\n",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			filename := path.Join(t.TempDir(), "CHANGELOG.md")
+
+			if test.existing != nil {
+				writeTestFile(t, filename, *test.existing)
+			}
+
+			rendered, err := renderChangelog(filename, section)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(rendered.contents) != test.want {
+				t.Errorf("got:\n%s\nwant:\n%s", rendered.contents, test.want)
+			}
+
+			// rendering never writes
+			if contents, err := os.ReadFile(filename); test.existing == nil && err == nil {
+				t.Errorf("expected %s not to be written, got %s", filename, contents)
+			} else if test.existing != nil && string(contents) != *test.existing {
+				t.Errorf("expected %s to be left as is, got %s", filename, contents)
+			}
+		})
+	}
+}
+
+func TestPlan(t *testing.T) {
+	packages := []*node.PackageInfo{
+		{Name: "b", Version: "1.2.3"},
+		{Name: "a", Version: "0.1.0"},
+	}
+
+	releases, err := Plan(packages, []*Intent{
+		{File: "b-1.yml", Action: "b", Bump: version.Patch, Summary: "fix y"},
+		{File: "a-1.yml", Action: "a", Bump: version.Patch, Summary: "fix x"},
+		{File: "b-2.yml", Action: "b", Bump: version.Minor, Summary: "add z"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		name    string
+		next    string
+		intents int
+	}{
+		{"a", "0.1.1", 1},
+		{"b", "1.3.0", 2},
+	}
+
+	if len(releases) != len(want) {
+		t.Fatalf("expected %d releases, got %d", len(want), len(releases))
+	}
+
+	for i, r := range releases {
+		if r.Package.Name != want[i].name || r.Next.String() != want[i].next || len(r.Intents) != want[i].intents {
+			t.Errorf("got %s %s with %d intents, want %s %s with %d", r.Package.Name, r.Next, len(r.Intents), want[i].name, want[i].next, want[i].intents)
+		}
+	}
+
+	if _, err := Plan(packages, []*Intent{{File: "c-1.yml", Action: "c", Bump: version.Patch, Summary: "fix"}}); err == nil || !strings.Contains(err.Error(), "action c, which does not exist") {
+		t.Errorf("expected an error for an unknown action, got %v", err)
+	}
+}
+
+func TestRelease(t *testing.T) {
+	root := t.TempDir()
+	dir := path.Join(root, "actions", "a")
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	writeTestFile(t, path.Join(dir, "package.json"), "{\n  \"name\": \"a\",\n  \"version\": \"1.0.0\"\n}\n")
+
+	for _, intent := range []*Intent{
+		{Action: "a", Bump: version.Minor, Summary: "add x"},
+		{Action: "a", Bump: version.Patch, Summary: "fix y\nin two lines"},
+	} {
+		if _, err := WriteIntent(root, intent); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// files that are not intents are left alone
+	writeTestFile(t, path.Join(root, Directory, "README.md"), "intents")
+
+	intents, err := ReadIntents(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	releases, err := Plan([]*node.PackageInfo{{Name: "a", Version: "1.0.0", Path: dir}}, intents)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := releases[0]
+
+	if err := r.Apply(); err == nil {
+		t.Errorf("expected applying a release that is not rendered to fail")
+	}
+
+	if err := r.Render(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Apply(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := r.Consume(); err != nil {
+		t.Fatal(err)
+	}
+
+	packageJSON, err := os.ReadFile(path.Join(dir, "package.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "{\n  \"name\": \"a\",\n  \"version\": \"1.1.0\"\n}\n"; string(packageJSON) != want {
+		t.Errorf("got package.json:\n%s\nwant:\n%s", packageJSON, want)
+	}
+
+	changelog, err := os.ReadFile(path.Join(dir, "CHANGELOG.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := "# Changelog\n\n## 1.1.0\n\n### Minor changes\n\n- add x\n\n### Patch changes\n\n- fix y\n  in two lines\n"
+	if string(changelog) != want {
+		t.Errorf("got CHANGELOG.md:\n%s\nwant:\n%s", changelog, want)
+	}
+
+	remaining, err := ReadIntents(root)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(remaining) != 0 {
+		t.Errorf("expected every intent to be consumed, got %d", len(remaining))
+	}
+}
+
+func TestReadIntents(t *testing.T) {
+	tests := []struct {
+		name     string
+		contents string
+		err      string
+	}{
+		{
+			name:     "valid",
+			contents: "action: a\nbump: minor\nsummary: add x\n",
+		},
+		{
+			name:     "invalid bump",
+			contents: "action: a\nbump: huge\nsummary: add x\n",
+			err:      "invalid bump huge",
+		},
+		{
+			name:     "missing action",
+			contents: "bump: minor\nsummary: add x\n",
+			err:      "missing action",
+		},
+		{
+			name:     "missing summary",
+			contents: "action: a\nbump: minor\nsummary: ' '\n",
+			err:      "missing summary",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			root := t.TempDir()
+
+			if err := os.MkdirAll(path.Join(root, Directory), 0755); err != nil {
+				t.Fatal(err)
+			}
+
+			writeTestFile(t, path.Join(root, Directory, "a-1234.yml"), test.contents)
+
+			intents, err := ReadIntents(root)
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected an error containing %q, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(intents) != 1 || intents[0].Action != "a" || intents[0].Bump != version.Minor || intents[0].File != path.Join(root, Directory, "a-1234.yml") {
+				t.Errorf("unexpected intents %+v", intents)
+			}
+		})
+	}
+}
+
+func writeTestFile(t *testing.T, filename, contents string) {
+	t.Helper()
+
+	if err := os.WriteFile(filename, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func stringPointer(s string) *string {
+	return &s
+}
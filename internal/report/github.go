@@ -0,0 +1,51 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// WriteGithubOutput appends the deployed actions and their commit SHAs to the file
+// referenced by $GITHUB_OUTPUT, if it is set.
+func (r *Report) WriteGithubOutput() error {
+	filename := os.Getenv("GITHUB_OUTPUT")
+	if filename == "" {
+		return nil
+	}
+
+	deployed := []string{}
+	shas := make(map[string]string)
+
+	for _, result := range r.Actions {
+		if result.Status != StatusSuccess || result.SHA == "" {
+			continue
+		}
+
+		deployed = append(deployed, result.Name)
+		shas[result.Name] = result.SHA
+	}
+
+	deployedJSON, err := json.Marshal(deployed)
+	if err != nil {
+		return err
+	}
+
+	shasJSON, err := json.Marshal(shas)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("could not open %s: %v", filename, err)
+	}
+
+	defer f.Close()
+
+	if _, err := fmt.Fprintf(f, "deployed=%s\nshas=%s\n", deployedJSON, shasJSON); err != nil {
+		return fmt.Errorf("could not write to %s: %v", filename, err)
+	}
+
+	return nil
+}
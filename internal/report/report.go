@@ -0,0 +1,200 @@
+package report
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+type Format string
+
+const (
+	FormatJSON  Format = "json"
+	FormatJUnit Format = "junit"
+)
+
+func ParseFormat(value string) (Format, error) {
+	switch Format(value) {
+	case FormatJSON, FormatJUnit:
+		return Format(value), nil
+	}
+
+	return "", fmt.Errorf("unsupported report format: %s, expected json or junit", value)
+}
+
+type Status string
+
+const (
+	StatusSuccess Status = "success"
+	StatusFailed  Status = "failed"
+	StatusSkipped Status = "skipped"
+)
+
+type ActionResult struct {
+	Name           string  `json:"name"`
+	Repository     string  `json:"repository"`
+	Status         Status  `json:"status"`
+	BuildDuration  float64 `json:"buildDuration"`
+	DeployDuration float64 `json:"deployDuration"`
+	SHA            string  `json:"sha,omitempty"`
+	Tag            string  `json:"tag,omitempty"`
+	Error          string  `json:"error,omitempty"`
+}
+
+type Report struct {
+	Command  string          `json:"command"`
+	Started  time.Time       `json:"started"`
+	Duration float64         `json:"duration"`
+	Actions  []*ActionResult `json:"actions"`
+}
+
+func New(command string) *Report {
+	return &Report{
+		Command: command,
+		Started: time.Now(),
+	}
+}
+
+func (r *Report) Add(result *ActionResult) *ActionResult {
+	r.Actions = append(r.Actions, result)
+
+	return result
+}
+
+func (a *ActionResult) Fail(err error) {
+	a.Status = StatusFailed
+	a.Error = err.Error()
+}
+
+func (r *Report) Finish() {
+	r.Duration = time.Since(r.Started).Seconds()
+}
+
+func (r *Report) Write(format Format, filename string) error {
+	var contents []byte
+	var err error
+
+	switch format {
+	case FormatJSON:
+		contents, err = json.MarshalIndent(r, "", "  ")
+	case FormatJUnit:
+		contents, err = r.junit()
+	default:
+		return fmt.Errorf("unsupported report format: %s", format)
+	}
+
+	if err != nil {
+		return fmt.Errorf("could not create %s report: %v", format, err)
+	}
+
+	if err := os.WriteFile(filename, append(contents, '\n'), 0644); err != nil {
+		return fmt.Errorf("could not write report to %s: %v", filename, err)
+	}
+
+	return nil
+}
+
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:"testsuites"`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name       string           `xml:"name,attr"`
+	ClassName  string           `xml:"classname,attr"`
+	Time       string           `xml:"time,attr"`
+	Properties *junitProperties `xml:"properties,omitempty"`
+	Failure    *junitFailure    `xml:"failure,omitempty"`
+	Skipped    *struct{}        `xml:"skipped,omitempty"`
+}
+
+type junitProperties struct {
+	Properties []junitProperty `xml:"property"`
+}
+
+type junitProperty struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Content string `xml:",chardata"`
+}
+
+func (r *Report) junit() ([]byte, error) {
+	suite := junitTestSuite{
+		Name:      "gamma " + r.Command,
+		Tests:     len(r.Actions),
+		Time:      formatSeconds(r.Duration),
+		Timestamp: r.Started.Format(time.RFC3339),
+	}
+
+	for _, result := range r.Actions {
+		testCase := junitTestCase{
+			Name:      result.Name,
+			ClassName: result.Repository,
+			Time:      formatSeconds(result.BuildDuration + result.DeployDuration),
+		}
+
+		var properties []junitProperty
+		properties = appendProperty(properties, "buildDuration", formatSeconds(result.BuildDuration))
+		properties = appendProperty(properties, "deployDuration", formatSeconds(result.DeployDuration))
+		properties = appendProperty(properties, "sha", result.SHA)
+		properties = appendProperty(properties, "tag", result.Tag)
+
+		testCase.Properties = &junitProperties{properties}
+
+		switch result.Status {
+		case StatusFailed:
+			suite.Failures++
+			testCase.Failure = &junitFailure{
+				Message: firstLine(result.Error),
+				Content: result.Error,
+			}
+		case StatusSkipped:
+			suite.Skipped++
+			testCase.Skipped = &struct{}{}
+		}
+
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	contents, err := xml.MarshalIndent(junitTestSuites{TestSuites: []junitTestSuite{suite}}, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), contents...), nil
+}
+
+func appendProperty(properties []junitProperty, name, value string) []junitProperty {
+	if value == "" {
+		return properties
+	}
+
+	return append(properties, junitProperty{name, value})
+}
+
+func formatSeconds(seconds float64) string {
+	return fmt.Sprintf("%.3f", seconds)
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+
+	return line
+}
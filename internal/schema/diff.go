@@ -0,0 +1,152 @@
+package schema
+
+import (
+	"fmt"
+)
+
+// ChangeKind is how a change to an action's interface affects the workflows using it.
+type ChangeKind string
+
+const (
+	// ChangeBreaking can break workflows using the action, and needs a major version bump
+	ChangeBreaking ChangeKind = "breaking"
+	// ChangeFeature adds to the action's interface
+	ChangeFeature ChangeKind = "feature"
+	// ChangeFix changes the action's interface without affecting how it is used
+	ChangeFix ChangeKind = "fix"
+)
+
+type Change struct {
+	Kind    ChangeKind `json:"kind"`
+	Message string     `json:"message"`
+}
+
+// Diff classifies every change to the inputs, outputs and metadata between two versions of an action.
+func Diff(previous, current *Config) []*Change {
+	var changes []*Change
+
+	add := func(kind ChangeKind, format string, a ...any) {
+		changes = append(changes, &Change{kind, fmt.Sprintf(format, a...)})
+	}
+
+	for _, name := range previous.InputNames() {
+		before := (*previous.Inputs)[name]
+
+		after, ok := current.input(name)
+		if !ok {
+			add(ChangeBreaking, "input %s was removed", name)
+
+			continue
+		}
+
+		switch {
+		case after.needsValue() && !before.needsValue():
+			add(ChangeBreaking, "input %s is now required", name)
+		case before.isRequired() && !after.isRequired():
+			add(ChangeFix, "input %s is no longer required", name)
+		case !before.isRequired() && after.isRequired():
+			add(ChangeFix, "input %s is now required, but has a default", name)
+		}
+
+		if stringValue(before.Default) != stringValue(after.Default) {
+			add(ChangeFix, "the default of input %s changed from %q to %q", name, stringValue(before.Default), stringValue(after.Default))
+		}
+
+		if before.Description != after.Description {
+			add(ChangeFix, "the description of input %s changed", name)
+		}
+
+		if stringValue(before.DeprecationMessage) != stringValue(after.DeprecationMessage) {
+			add(ChangeFix, "the deprecation message of input %s changed", name)
+		}
+	}
+
+	for _, name := range current.InputNames() {
+		if _, ok := previous.input(name); ok {
+			continue
+		}
+
+		if input := (*current.Inputs)[name]; input.needsValue() {
+			add(ChangeBreaking, "required input %s was added", name)
+		} else {
+			add(ChangeFeature, "input %s was added", name)
+		}
+	}
+
+	for _, name := range previous.OutputNames() {
+		after, ok := current.output(name)
+		if !ok {
+			add(ChangeBreaking, "output %s was removed", name)
+
+			continue
+		}
+
+		if (*previous.Outputs)[name].Description != after.Description {
+			add(ChangeFix, "the description of output %s changed", name)
+		}
+	}
+
+	for _, name := range current.OutputNames() {
+		if _, ok := previous.output(name); !ok {
+			add(ChangeFeature, "output %s was added", name)
+		}
+	}
+
+	if previous.Runs.Using != current.Runs.Using {
+		add(ChangeFeature, "runs.using changed from %s to %s", previous.Runs.Using, current.Runs.Using)
+	}
+
+	if previous.Name != current.Name || previous.Description != current.Description {
+		add(ChangeFix, "the name or description of the action changed")
+	}
+
+	return changes
+}
+
+// HasBreakingChanges returns whether any of the changes needs a major version bump.
+func HasBreakingChanges(changes []*Change) bool {
+	for _, change := range changes {
+		if change.Kind == ChangeBreaking {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (c *Config) input(name string) (Input, bool) {
+	if c.Inputs == nil {
+		return Input{}, false
+	}
+
+	input, ok := (*c.Inputs)[name]
+
+	return input, ok
+}
+
+func (c *Config) output(name string) (Output, bool) {
+	if c.Outputs == nil {
+		return Output{}, false
+	}
+
+	output, ok := (*c.Outputs)[name]
+
+	return output, ok
+}
+
+func (i *Input) isRequired() bool {
+	return i.Required != nil && *i.Required
+}
+
+// needsValue returns whether workflows have to set the input, as it is required without a default.
+func (i *Input) needsValue() bool {
+	return i.isRequired() && i.Default == nil
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
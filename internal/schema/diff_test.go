@@ -0,0 +1,142 @@
+package schema
+
+import (
+	"reflect"
+	"testing"
+)
+
+const diffBase = `name: action
+description: action description
+inputs:
+  version:
+    description: The version
+    required: true
+  token:
+    description: The token
+    default: abc
+outputs:
+  url:
+    description: The url
+runs:
+  using: node20
+  main: dist/index.js
+`
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		want    []*Change
+	}{
+		{
+			name:    "unchanged",
+			current: diffBase,
+		},
+		{
+			name: "removed input and output",
+			current: `name: action
+description: action description
+inputs:
+  version:
+    description: The version
+    required: true
+runs:
+  using: node20
+  main: dist/index.js
+`,
+			want: []*Change{
+				{ChangeBreaking, "input token was removed"},
+				{ChangeBreaking, "output url was removed"},
+			},
+		},
+		{
+			name: "required inputs",
+			current: `name: action
+description: action description
+inputs:
+  version:
+    description: The version
+  token:
+    description: The token
+    required: true
+  ref:
+    description: The ref
+    required: true
+  debug:
+    description: Log more
+outputs:
+  url:
+    description: The url
+runs:
+  using: node20
+  main: dist/index.js
+`,
+			want: []*Change{
+				{ChangeFix, "input version is no longer required"},
+				{ChangeBreaking, "input token is now required"},
+				{ChangeFix, `the default of input token changed from "abc" to ""`},
+				{ChangeBreaking, "required input ref was added"},
+				{ChangeFeature, "input debug was added"},
+			},
+		},
+		{
+			name: "fixes and features",
+			current: `name: action
+description: a better description
+inputs:
+  version:
+    description: The version to install
+    required: true
+  token:
+    description: The token
+    default: abc
+    required: true
+outputs:
+  url:
+    description: The url
+  path:
+    description: The path
+runs:
+  using: node24
+  main: dist/index.js
+`,
+			want: []*Change{
+				{ChangeFix, "the description of input version changed"},
+				{ChangeFix, "input token is now required, but has a default"},
+				{ChangeFeature, "output path was added"},
+				{ChangeFeature, "runs.using changed from node20 to node24"},
+				{ChangeFix, "the name or description of the action changed"},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			previous, err := ParseConfig("previous/action.yml", []byte(diffBase))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			current, err := ParseConfig("current/action.yml", []byte(test.current))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			changes := Diff(previous, current)
+
+			if !reflect.DeepEqual(changes, test.want) {
+				t.Errorf("unexpected changes:")
+				for _, change := range changes {
+					t.Errorf("  got %s: %s", change.Kind, change.Message)
+				}
+				for _, change := range test.want {
+					t.Errorf("  want %s: %s", change.Kind, change.Message)
+				}
+			}
+
+			if HasBreakingChanges(changes) != HasBreakingChanges(test.want) {
+				t.Errorf("expected HasBreakingChanges to be %t", HasBreakingChanges(test.want))
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+)
+
+// InputsMarkdown renders the inputs of the config as a markdown table, including their types.
+func (c *Config) InputsMarkdown() string {
+	rows := [][]string{{"Input", "Description", "Type", "Required", "Default"}}
+
+	for _, name := range c.InputNames() {
+		input := (*c.Inputs)[name]
+
+		required := "no"
+		if input.Required != nil && *input.Required {
+			required = "yes"
+		}
+
+		var value string
+		if input.Default != nil {
+			value = "`" + *input.Default + "`"
+		}
+
+		rows = append(rows, []string{"`" + name + "`", input.Description, input.typeDescription(), required, value})
+	}
+
+	return markdownTable(rows)
+}
+
+// OutputsMarkdown renders the outputs of the config as a markdown table.
+func (c *Config) OutputsMarkdown() string {
+	rows := [][]string{{"Output", "Description"}}
+
+	for _, name := range c.OutputNames() {
+		rows = append(rows, []string{"`" + name + "`", (*c.Outputs)[name].Description})
+	}
+
+	return markdownTable(rows)
+}
+
+func (i *Input) typeDescription() string {
+	description := string(i.InputType())
+
+	if i.Choices != nil {
+		var choices []string
+		for _, choice := range *i.Choices {
+			choices = append(choices, "`"+choice+"`")
+		}
+
+		description = fmt.Sprintf("%s: %s", description, strings.Join(choices, ", "))
+	}
+
+	if i.Pattern != nil {
+		description = fmt.Sprintf("%s matching `%s`", description, *i.Pattern)
+	}
+
+	return description
+}
+
+func markdownTable(rows [][]string) string {
+	var b strings.Builder
+
+	for i, row := range rows {
+		var cells []string
+		for _, cell := range row {
+			cell = strings.ReplaceAll(strings.TrimSpace(cell), "|", "\\|")
+			cells = append(cells, strings.ReplaceAll(cell, "\n", "<br>"))
+		}
+
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+
+		if i == 0 {
+			b.WriteString(strings.Repeat("| --- ", len(row)) + "|\n")
+		}
+	}
+
+	return b.String()
+}
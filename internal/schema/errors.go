@@ -0,0 +1,71 @@
+package schema
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ConflictError is a field defined by both a config and a file it extends, which the strategy of the
+// extend does not resolve.
+type ConflictError struct {
+	Field    string
+	Existing string
+	Incoming string
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("conflicting %s, defined in both %s and %s", e.Field, e.Existing, e.Incoming)
+}
+
+func isConflict(err error) bool {
+	if errs, ok := err.(Errors); ok {
+		for _, err := range errs {
+			if isConflict(err) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	var conflict *ConflictError
+
+	return errors.As(err, &conflict)
+}
+
+// Errors collects multiple errors so they can be reported together.
+type Errors []error
+
+func (e Errors) Append(err error) Errors {
+	if err == nil {
+		return e
+	}
+
+	if errs, ok := err.(Errors); ok {
+		return append(e, errs...)
+	}
+
+	return append(e, err)
+}
+
+// Err returns nil if there are no errors, the error itself if there is one, and otherwise all of them.
+func (e Errors) Err() error {
+	switch len(e) {
+	case 0:
+		return nil
+	case 1:
+		return e[0]
+	}
+
+	return e
+}
+
+func (e Errors) Error() string {
+	var messages []string
+	for _, err := range e {
+		messages = append(messages, err.Error())
+	}
+
+	return strings.Join(messages, "\n")
+}
@@ -0,0 +1,49 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// fragment returns the part of a config an extend refers to after a #. This is either a path such as
+// /inputs/version, which keeps its place in the config, or the name of one of the config's fragments.
+func (c *Config) fragment(reference string) (*Config, error) {
+	var mapping *yaml.Node
+
+	if strings.HasPrefix(reference, "/") {
+		path := strings.Split(strings.TrimPrefix(reference, "/"), "/")
+
+		mapping = lookupPath(c.mapping(), path)
+		if mapping == nil {
+			return nil, fmt.Errorf("%s does not exist in %s", reference, c.Path)
+		}
+
+		for i := len(path) - 1; i >= 0; i-- {
+			parent := newMapping()
+			appendMappingValue(parent, mappingKey(lookupPath(c.mapping(), path[:i]), path[i]), mapping)
+
+			mapping = parent
+		}
+	} else {
+		mapping = mappingValue(c.fragments, reference)
+		if mapping == nil {
+			return nil, fmt.Errorf("fragment %s does not exist in %s", reference, c.Path)
+		}
+
+		if mapping.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("fragment %s in %s is not a mapping", reference, c.Path)
+		}
+	}
+
+	return &Config{
+		Path:      c.Path,
+		Node:      &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{mapping}},
+		sources:   c.sources,
+		strict:    c.strict,
+		fragments: c.fragments,
+		extends:   c.extends,
+		overrides: c.overrides,
+	}, nil
+}
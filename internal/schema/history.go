@@ -0,0 +1,112 @@
+package schema
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ExtendResult is what happened to a field extended from another file while resolving a config.
+type ExtendResult struct {
+	// File is the file the extend is defined in
+	File    string `json:"file"`
+	From    string `json:"from"`
+	Field   string `json:"field"`
+	Applied bool   `json:"applied"`
+	// Reason is why the field was skipped
+	Reason string `json:"reason,omitempty"`
+	// Conflict is whether the field was skipped because the config already defines it
+	Conflict bool `json:"conflict,omitempty"`
+}
+
+// Override is a value from one file that replaced the value of the same field from another.
+type Override struct {
+	Field      string   `json:"field"`
+	Source     Location `json:"source"`
+	Overridden Location `json:"overridden"`
+}
+
+// Field is a value of a resolved config, along with where it was defined.
+type Field struct {
+	Path  string `json:"path"`
+	Value string `json:"value"`
+	Location
+}
+
+type Location struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+// Extends returns every field extended while resolving the config, including by the files it extends.
+func (c *Config) Extends() []*ExtendResult {
+	return c.extends
+}
+
+// Overrides returns every value that replaced another while resolving the config.
+func (c *Config) Overrides() []*Override {
+	return c.overrides
+}
+
+// Fields returns every value of the resolved config, in the order they appear in.
+func (c *Config) Fields() []*Field {
+	var fields []*Field
+
+	var walk func(path string, node *yaml.Node)
+	walk = func(path string, node *yaml.Node) {
+		switch node.Kind {
+		case yaml.MappingNode:
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				name := node.Content[i].Value
+				if path != "" {
+					name = path + "." + name
+				}
+
+				walk(name, node.Content[i+1])
+			}
+		case yaml.SequenceNode:
+			for i, child := range node.Content {
+				walk(fmt.Sprintf("%s[%d]", path, i), child)
+			}
+		default:
+			fields = append(fields, &Field{
+				Path:     path,
+				Value:    node.Value,
+				Location: c.Location(node),
+			})
+		}
+	}
+
+	walk("", c.mapping())
+
+	return fields
+}
+
+// Location returns the file and line a node of the resolved config was defined at.
+func (c *Config) Location(node *yaml.Node) Location {
+	return Location{c.Source(node), node.Line}
+}
+
+func (c *Config) recordExtend(from, field string, err error) {
+	result := &ExtendResult{
+		File:    c.Path,
+		From:    from,
+		Field:   field,
+		Applied: err == nil,
+	}
+
+	if err != nil {
+		result.Reason = err.Error()
+		result.Conflict = isConflict(err)
+	}
+
+	c.extends = append(c.extends, result)
+}
+
+func (c *Config) recordOverride(field string, overridden, source *yaml.Node) {
+	c.overrides = append(c.overrides, &Override{
+		Field:      field,
+		Source:     c.Location(source),
+		Overridden: c.Location(overridden),
+	})
+}
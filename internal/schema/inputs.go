@@ -0,0 +1,150 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gravitational/gamma/internal/utils"
+)
+
+// typedInputFields are the fields of an input only gamma uses, which are removed when publishing.
+var typedInputFields = []string{"type", "choices", "pattern"}
+
+// checkInputs makes sure the type annotations of each input are valid, and that its default matches them.
+func checkInputs(config *Config) error {
+	inputs := mappingValue(config.mapping(), "inputs")
+	if inputs == nil || config.Inputs == nil {
+		return nil
+	}
+
+	var errs Errors
+
+	for i := 0; i+1 < len(inputs.Content); i += 2 {
+		name, node := inputs.Content[i].Value, inputs.Content[i+1]
+		input := (*config.Inputs)[name]
+
+		if err := checkInput(name, &input); err != nil {
+			errs = errs.Append(fmt.Errorf("%s: %v", config.Position(node), err))
+		}
+	}
+
+	return errs.Err()
+}
+
+func checkInput(name string, input *Input) error {
+	inputType := input.InputType()
+
+	switch inputType {
+	case InputTypeString, InputTypeBoolean, InputTypeNumber, InputTypeEnum, InputTypeList:
+	default:
+		return fmt.Errorf("input %s has an unsupported type %s, expected string, boolean, number, enum or list", name, inputType)
+	}
+
+	if inputType == InputTypeEnum && (input.Choices == nil || len(*input.Choices) == 0) {
+		return fmt.Errorf("input %s is an enum, so it needs choices", name)
+	}
+
+	if inputType != InputTypeEnum && input.Choices != nil {
+		return fmt.Errorf("input %s has choices, which are only used by enum inputs", name)
+	}
+
+	var pattern *regexp.Regexp
+	if input.Pattern != nil {
+		p, err := regexp.Compile(*input.Pattern)
+		if err != nil {
+			return fmt.Errorf("input %s has an invalid pattern: %v", name, err)
+		}
+
+		pattern = p
+	}
+
+	// a default using an expression is only known when the action runs
+	if input.Default == nil || strings.Contains(*input.Default, "${{") {
+		return nil
+	}
+
+	value := *input.Default
+
+	values := []string{value}
+	if inputType == InputTypeList {
+		values = SplitList(value)
+	}
+
+	for _, v := range values {
+		if err := checkInputValue(inputType, input.Choices, pattern, v); err != nil {
+			return fmt.Errorf("input %s has an invalid default: %v", name, err)
+		}
+	}
+
+	return nil
+}
+
+func checkInputValue(inputType InputType, choices *[]string, pattern *regexp.Regexp, value string) error {
+	switch inputType {
+	case InputTypeBoolean:
+		if value != "true" && value != "false" {
+			return fmt.Errorf("%q is not true or false", value)
+		}
+	case InputTypeNumber:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+	case InputTypeEnum:
+		if !utils.Contains(*choices, value) {
+			return fmt.Errorf("%q is not one of %s", value, strings.Join(*choices, ", "))
+		}
+	}
+
+	if pattern != nil && !pattern.MatchString(value) {
+		return fmt.Errorf("%q does not match %s", value, pattern)
+	}
+
+	return nil
+}
+
+// InputType returns the type of the input, which is a string unless annotated otherwise.
+func (i *Input) InputType() InputType {
+	if i.Type == nil {
+		return InputTypeString
+	}
+
+	return *i.Type
+}
+
+// SplitList splits the value of a list input, which is separated by new lines or commas.
+func SplitList(value string) []string {
+	var values []string
+
+	for _, line := range strings.Split(value, "\n") {
+		for _, v := range strings.Split(line, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				values = append(values, v)
+			}
+		}
+	}
+
+	return values
+}
+
+// Published returns the config as it is published to the action's repository, without the fields
+// only gamma uses.
+func (c *Config) Published() *yaml.Node {
+	document := cloneNode(c.Node, nil)
+
+	inputs := mappingValue(document.Content[0], "inputs")
+	if inputs == nil || inputs.Kind != yaml.MappingNode {
+		return document
+	}
+
+	for i := 0; i+1 < len(inputs.Content); i += 2 {
+		for _, field := range typedInputFields {
+			removeMappingKey(inputs.Content[i+1], field)
+		}
+	}
+
+	return document
+}
@@ -0,0 +1,159 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+type jsonSchema = map[string]interface{}
+
+// JSONSchema returns a JSON Schema for gamma's action.yml format, generated from CustomConfig so editors
+// can validate and complete action.yml and shared files.
+func JSONSchema() ([]byte, error) {
+	g := &schemaGenerator{definitions: make(map[string]jsonSchema)}
+
+	root := g.object(reflect.TypeOf(CustomConfig{}))
+	root["$schema"] = "http://json-schema.org/draft-07/schema#"
+	root["title"] = "gamma action.yml"
+	root["definitions"] = g.definitions
+
+	return json.MarshalIndent(root, "", "  ")
+}
+
+type schemaGenerator struct {
+	definitions map[string]jsonSchema
+}
+
+func (g *schemaGenerator) schema(t reflect.Type, tag reflect.StructTag) jsonSchema {
+	if t.Kind() == reflect.Pointer {
+		return g.schema(t.Elem(), tag)
+	}
+
+	if tag.Get("jsonschema") == "scalar" {
+		return jsonSchema{"type": []string{"string", "number", "boolean"}}
+	}
+
+	switch t {
+	case reflect.TypeOf(Runs{}):
+		return g.define(t, g.runs)
+	case reflect.TypeOf(Branding{}):
+		return g.define(t, g.branding)
+	// env and with values are often written as numbers or booleans
+	case reflect.TypeOf(map[string]string{}):
+		return jsonSchema{"type": "object", "additionalProperties": jsonSchema{"type": []string{"string", "number", "boolean"}}}
+	case reflect.TypeOf(Expression("")):
+		return jsonSchema{"type": []string{"string", "number", "boolean"}}
+	case reflect.TypeOf(InputType("")):
+		return jsonSchema{"enum": []InputType{InputTypeString, InputTypeBoolean, InputTypeNumber, InputTypeEnum, InputTypeList}}
+	case reflect.TypeOf(Strategy("")):
+		return jsonSchema{"enum": []Strategy{StrategyError, StrategyMerge, StrategyReplace, StrategyAppend, StrategyPrepend}}
+	case reflect.TypeOf(Args{}):
+		return jsonSchema{
+			"anyOf": []jsonSchema{
+				{"type": "string"},
+				{"type": "array", "items": jsonSchema{"type": "string"}},
+			},
+		}
+	case reflect.TypeOf(IncludeItem{}):
+		return jsonSchema{
+			"anyOf": []jsonSchema{
+				{"type": "string"},
+				g.object(t),
+			},
+		}
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		return g.define(t, g.object)
+	case reflect.Map:
+		return jsonSchema{"type": "object", "additionalProperties": g.schema(t.Elem(), "")}
+	case reflect.Slice:
+		return jsonSchema{"type": "array", "items": g.schema(t.Elem(), "")}
+	case reflect.Bool:
+		return jsonSchema{"type": "boolean"}
+	case reflect.Int, reflect.Int64:
+		return jsonSchema{"type": "integer"}
+	}
+
+	return jsonSchema{"type": "string"}
+}
+
+// define adds a named type to the schema's definitions once, returning a reference to it.
+func (g *schemaGenerator) define(t reflect.Type, generate func(reflect.Type) jsonSchema) jsonSchema {
+	if _, ok := g.definitions[t.Name()]; !ok {
+		// reserve the name first, so types referring to themselves do not recurse forever
+		g.definitions[t.Name()] = nil
+		g.definitions[t.Name()] = generate(t)
+	}
+
+	return jsonSchema{"$ref": "#/definitions/" + t.Name()}
+}
+
+// object describes a struct by the yaml names of its fields, where fields tagged jsonschema:"required"
+// are required.
+func (g *schemaGenerator) object(t reflect.Type) jsonSchema {
+	properties := make(jsonSchema)
+
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+
+		properties[name] = g.schema(field.Type, field.Tag)
+
+		if field.Tag.Get("jsonschema") == "required" {
+			required = append(required, name)
+		}
+	}
+
+	object := jsonSchema{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+
+	if len(required) > 0 {
+		object["required"] = required
+	}
+
+	return object
+}
+
+// runs describes each kind of runs, which is decided by its using field. using is not required, as shared
+// files can define part of runs, such as steps.
+func (g *schemaGenerator) runs(_ reflect.Type) jsonSchema {
+	variants := []struct {
+		t     reflect.Type
+		using []string
+	}{
+		{reflect.TypeOf(JavascriptRun{}), []string{"node12", "node16", "node20", "node24"}},
+		{reflect.TypeOf(CompositeRun{}), []string{"composite"}},
+		{reflect.TypeOf(DockerRun{}), []string{"docker"}},
+	}
+
+	var anyOf []jsonSchema
+
+	for _, variant := range variants {
+		object := g.object(variant.t)
+		object["properties"].(jsonSchema)["using"] = jsonSchema{"enum": variant.using}
+
+		anyOf = append(anyOf, object)
+	}
+
+	return jsonSchema{"anyOf": anyOf}
+}
+
+func (g *schemaGenerator) branding(t reflect.Type) jsonSchema {
+	object := g.object(t)
+	object["properties"].(jsonSchema)["color"] = jsonSchema{"enum": brandingColors}
+	object["properties"].(jsonSchema)["icon"] = jsonSchema{"enum": brandingIcons}
+
+	return object
+}
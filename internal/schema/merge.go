@@ -0,0 +1,448 @@
+package schema
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gravitational/gamma/internal/utils"
+)
+
+func mergeConfigs(base, extension *Config, from *Extension) error {
+	// what the extension's own extends did is part of how the base was resolved
+	base.extends = append(base.extends, extension.extends...)
+	base.overrides = append(base.overrides, extension.overrides...)
+
+	if from.Include != nil {
+		// the values of an action extending another action take precedence over the other's
+		fallback := StrategyError
+		if strings.HasPrefix(from.From, "action:") {
+			fallback = StrategyMerge
+		}
+
+		var errs Errors
+
+		for _, include := range *from.Include {
+			include := include
+
+			err := mergeInclude(base, extension, &include, fallback)
+			base.recordExtend(from.From, include.Field, err)
+
+			if base.partial {
+				continue
+			}
+
+			// missing inputs, outputs, branding, author or runs have always been errors, but any other field the
+			// extension does not define was ignored, which only strict mode reports
+			if err != nil && !base.strict && include.Field != "" && !utils.Contains(extendableFields, include.Field) &&
+				lookupPath(extension.mapping(), strings.Split(include.Field, ".")) == nil {
+				continue
+			}
+
+			if err != nil && !base.strict {
+				return err
+			}
+
+			errs = errs.Append(err)
+		}
+
+		return errs.Err()
+	}
+
+	// without includes, the fields gamma has always extended are merged, with the values the base defines
+	// itself taking precedence. Strict mode reports the errors merging them, which are otherwise ignored.
+	var errs Errors
+
+	for _, field := range extendableFields {
+		value := mappingValue(extension.mapping(), field)
+		if value == nil {
+			continue
+		}
+
+		err := mergeSelection(base, extension, []string{field}, base.importNode(extension, value), StrategyMerge)
+		base.recordExtend(from.From, field, err)
+
+		errs = errs.Append(err)
+	}
+
+	if !base.strict || base.partial {
+		return nil
+	}
+
+	return errs.Err()
+}
+
+// mergeInclude merges the part of the extension selected by an include's field path, such as inputs,
+// runs.env or inputs.version, filtered by its include and exclude lists. The fallback strategy is used
+// when the include does not set one.
+func mergeInclude(base, extension *Config, include *ExtensionInclude, fallback Strategy) error {
+	if include.Field == "" {
+		return fmt.Errorf("extend of %s in %s is missing a field", extension.Path, base.Path)
+	}
+
+	path := strings.Split(include.Field, ".")
+
+	source := lookupPath(extension.mapping(), path)
+	if source == nil {
+		return fmt.Errorf("%s does not exist in %s", include.Field, extension.Path)
+	}
+
+	strategy, err := include.strategy(fallback)
+	if err != nil {
+		return err
+	}
+
+	selected, err := filterNode(base.importNode(extension, source), include, extension.Path)
+	if err != nil {
+		return err
+	}
+
+	if include.Before != nil || include.After != nil {
+		if include.Before != nil && include.After != nil {
+			return fmt.Errorf("%s from %s can not be inserted both before and after a step", include.Field, extension.Path)
+		}
+
+		if include.Strategy != nil || include.Override != nil {
+			return fmt.Errorf("%s from %s can not use a strategy when inserted before or after a step", include.Field, extension.Path)
+		}
+
+		return insertSteps(base, extension, path, selected, include)
+	}
+
+	return mergeSelection(base, extension, path, selected, strategy)
+}
+
+// insertSteps inserts the selected list of steps next to the step with the include's before or after id.
+func insertSteps(base, extension *Config, path []string, selected *yaml.Node, include *ExtensionInclude) error {
+	name := strings.Join(path, ".")
+
+	anchor, offset := include.Before, 0
+	if include.After != nil {
+		anchor, offset = include.After, 1
+	}
+
+	existing := lookupPath(base.mapping(), path)
+
+	if existing == nil || existing.Kind != yaml.SequenceNode || selected.Kind != yaml.SequenceNode {
+		return fmt.Errorf("%s needs to be a list in both %s and %s to insert steps at %s", name, base.Path, extension.Path, *anchor)
+	}
+
+	for i, step := range existing.Content {
+		if id := mappingValue(step, "id"); id != nil && id.Value == *anchor {
+			content := make([]*yaml.Node, 0, len(existing.Content)+len(selected.Content))
+			content = append(content, existing.Content[:i+offset]...)
+			content = append(content, selected.Content...)
+			content = append(content, existing.Content[i+offset:]...)
+
+			existing.Content = content
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("could not insert %s from %s, as there is no step with id %s", name, extension.Path, *anchor)
+}
+
+// filterNode applies an include's include and exclude lists to a copy of the selected node. Entries in
+// the lists are paths relative to the selected node, and included entries can be renamed with as.
+func filterNode(node *yaml.Node, include *ExtensionInclude, source string) (*yaml.Node, error) {
+	if include.Include == nil && include.Exclude == nil {
+		return node, nil
+	}
+
+	if node.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s in %s is not a mapping, so it can not be filtered with include or exclude", include.Field, source)
+	}
+
+	if include.Include != nil {
+		filtered := newMapping()
+		filtered.Line, filtered.Column = node.Line, node.Column
+
+		for _, item := range *include.Include {
+			path := strings.Split(item.Name, ".")
+
+			if item.As != "" && len(path) > 1 {
+				return nil, fmt.Errorf("%s.%s in %s can not be renamed, only direct entries can", include.Field, item.Name, source)
+			}
+
+			from, to := node, filtered
+
+			for i, segment := range path {
+				key := mappingKey(from, segment)
+				if key == nil {
+					return nil, fmt.Errorf("%s.%s does not exist in %s", include.Field, item.Name, source)
+				}
+
+				from = mappingValue(from, segment)
+
+				if i == len(path)-1 {
+					// the node is already a copy, so its key can be renamed in place
+					if item.As != "" {
+						key.Value = item.As
+					}
+
+					setMappingValue(to, key, from)
+
+					break
+				}
+
+				child := mappingValue(to, segment)
+				if child == nil {
+					child = newMapping()
+					child.Line, child.Column = from.Line, from.Column
+
+					setMappingValue(to, key, child)
+				}
+
+				to = child
+			}
+		}
+
+		node = filtered
+	}
+
+	if include.Exclude != nil {
+		for _, exclude := range *include.Exclude {
+			path := strings.Split(exclude, ".")
+
+			if parent := lookupPath(node, path[:len(path)-1]); parent != nil {
+				removeMappingKey(parent, path[len(path)-1])
+			}
+		}
+	}
+
+	return node, nil
+}
+
+// mergeSelection merges a node into the base at the given path, creating any mappings along the way.
+// When the base already defines the path, each entry of a selected mapping is merged on its own, and
+// entries or values defined in both are resolved with the strategy.
+func mergeSelection(base, extension *Config, path []string, selected *yaml.Node, strategy Strategy) error {
+	parent := base.mapping()
+
+	for i, segment := range path {
+		existing := mappingValue(parent, segment)
+
+		if existing == nil {
+			key := base.importNode(extension, mappingKey(lookupPath(extension.mapping(), path[:i]), segment))
+			if key == nil {
+				key = newKey(segment)
+			}
+
+			// a comment above the first key is about the extension file as a whole
+			if i == 0 && len(extension.mapping().Content) > 0 && extension.mapping().Content[0].Value == segment {
+				key.HeadComment = ""
+			}
+
+			value := selected
+			if i < len(path)-1 {
+				value = newMapping()
+			}
+
+			if i == 0 {
+				insertField(parent, key, value)
+			} else {
+				setMappingValue(parent, key, value)
+			}
+
+			if i == len(path)-1 {
+				return nil
+			}
+
+			parent = value
+
+			continue
+		}
+
+		if i == len(path)-1 {
+			break
+		}
+
+		if existing.Kind != yaml.MappingNode {
+			return fmt.Errorf("can not extend %s from %s, as %s is not a mapping in %s", strings.Join(path, "."), extension.Path, strings.Join(path[:i+1], "."), base.Source(existing))
+		}
+
+		parent = existing
+	}
+
+	name := strings.Join(path, ".")
+	key := mappingKey(parent, path[len(path)-1])
+	existing := mappingValue(parent, path[len(path)-1])
+
+	if !mergesEntries(path, existing, selected, strategy) {
+		value, err := resolveConflict(base, extension, name, existing, selected, strategy)
+		if err != nil {
+			return err
+		}
+
+		setMappingValue(parent, key, value)
+
+		return nil
+	}
+
+	// the entries are merged into a copy, so a conflict in any of them leaves the base as it was
+	merged := *existing
+	merged.Content = append([]*yaml.Node{}, existing.Content...)
+
+	overrides := len(base.overrides)
+
+	var errs Errors
+
+	for i := 0; i+1 < len(selected.Content); i += 2 {
+		childKey, child := selected.Content[i], selected.Content[i+1]
+
+		current := mappingValue(&merged, childKey.Value)
+		if current == nil {
+			appendMappingValue(&merged, childKey, child)
+
+			continue
+		}
+
+		value, err := resolveConflict(base, extension, name+"."+childKey.Value, current, child, strategy)
+		if err != nil {
+			errs = errs.Append(err)
+
+			continue
+		}
+
+		setMappingValue(&merged, childKey, value)
+	}
+
+	if err := errs.Err(); err != nil {
+		base.overrides = base.overrides[:overrides]
+
+		return err
+	}
+
+	existing.Content = merged.Content
+
+	return nil
+}
+
+// mergesEntries returns whether the entries of mappings defined by both the base and an extension are
+// resolved one by one. Replacing swaps whole values, other than the entries of inputs and outputs, and
+// runs of different kinds are never combined.
+func mergesEntries(path []string, existing, selected *yaml.Node, strategy Strategy) bool {
+	if existing.Kind != yaml.MappingNode || selected.Kind != yaml.MappingNode {
+		return false
+	}
+
+	name := strings.Join(path, ".")
+
+	if name == "runs" && differentRuns(existing, selected) {
+		return false
+	}
+
+	if strategy == StrategyReplace {
+		return name == "inputs" || name == "outputs"
+	}
+
+	return true
+}
+
+// differentRuns returns whether two runs set different values for using.
+func differentRuns(a, b *yaml.Node) bool {
+	usingA, usingB := mappingValue(a, "using"), mappingValue(b, "using")
+
+	return usingA != nil && usingB != nil && usingA.Value != usingB.Value
+}
+
+// resolveConflict decides the value of something defined by both the base and an extension.
+func resolveConflict(base, extension *Config, name string, existing, incoming *yaml.Node, strategy Strategy) (*yaml.Node, error) {
+	// the same value defined twice is not a conflict
+	if existing.Kind == yaml.ScalarNode && incoming.Kind == yaml.ScalarNode && existing.Value == incoming.Value {
+		return existing, nil
+	}
+
+	switch strategy {
+	case StrategyMerge:
+		return base.mergeNodes(name, incoming, existing), nil
+	case StrategyReplace:
+		base.recordOverride(name, existing, incoming)
+
+		return incoming, nil
+	case StrategyAppend:
+		return combineNodes(base, extension, name, existing, incoming)
+	case StrategyPrepend:
+		return combineNodes(base, extension, name, incoming, existing)
+	}
+
+	return nil, &ConflictError{Field: name, Existing: base.Source(existing), Incoming: extension.Path}
+}
+
+// combineNodes joins two values of the same kind, in order. Lists are concatenated, strings are joined
+// on separate lines and mappings take the keys they are missing from each other.
+func combineNodes(base, extension *Config, name string, first, second *yaml.Node) (*yaml.Node, error) {
+	if first.Kind != second.Kind {
+		return nil, fmt.Errorf("can not combine %s, as it is a different kind of value in %s and %s", name, base.Source(first), base.Source(second))
+	}
+
+	combined := *first
+
+	switch first.Kind {
+	case yaml.SequenceNode:
+		combined.Content = append(append([]*yaml.Node{}, first.Content...), second.Content...)
+	case yaml.ScalarNode:
+		combined.Value = strings.TrimRight(first.Value, "\n") + "\n" + second.Value
+		if combined.Style != yaml.LiteralStyle && combined.Style != yaml.FoldedStyle {
+			combined.Style = yaml.LiteralStyle
+		}
+	case yaml.MappingNode:
+		combined.Content = append([]*yaml.Node{}, first.Content...)
+
+		for i := 0; i+1 < len(second.Content); i += 2 {
+			key := second.Content[i]
+
+			if existing := mappingValue(&combined, key.Value); existing != nil {
+				return nil, &ConflictError{Field: name + "." + key.Value, Existing: base.Source(existing), Incoming: base.Source(second.Content[i+1])}
+			}
+
+			appendMappingValue(&combined, key, second.Content[i+1])
+		}
+	default:
+		return nil, fmt.Errorf("can not combine %s from %s", name, extension.Path)
+	}
+
+	return &combined, nil
+}
+
+// mergeNodes deep merges override over base, so mappings are merged key by key and any other value
+// in override replaces the one in base.
+func (c *Config) mergeNodes(name string, base, override *yaml.Node) *yaml.Node {
+	if base.Kind != yaml.MappingNode || override.Kind != yaml.MappingNode || (name == "runs" && differentRuns(base, override)) {
+		c.recordOverride(name, base, override)
+
+		return override
+	}
+
+	merged := *base
+	merged.Content = append([]*yaml.Node{}, base.Content...)
+
+	if override.HeadComment != "" {
+		merged.HeadComment = override.HeadComment
+	}
+
+	for i := 0; i+1 < len(override.Content); i += 2 {
+		key, value := override.Content[i], override.Content[i+1]
+
+		if existing := mappingValue(&merged, key.Value); existing != nil {
+			value = c.mergeNodes(name+"."+key.Value, existing, value)
+		}
+
+		setMappingValue(&merged, key, value)
+	}
+
+	return &merged
+}
+
+func lookupPath(node *yaml.Node, path []string) *yaml.Node {
+	for _, segment := range path {
+		node = mappingValue(node, segment)
+		if node == nil {
+			return nil
+		}
+	}
+
+	return node
+}
@@ -0,0 +1,640 @@
+package schema
+
+import (
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gravitational/gamma/internal/node"
+)
+
+const sharedConfig = `name: shared
+description: shared description
+author: shared author
+inputs:
+  version:
+    description: shared version
+    default: "1"
+  token:
+    description: shared token
+runs:
+  using: composite
+  steps:
+    - run: echo setup
+      shell: bash
+branding:
+  color: purple
+`
+
+func TestMergeConfigs(t *testing.T) {
+	tests := []struct {
+		name   string
+		shared string
+		action string
+		strict bool
+		want   string
+		err    string
+	}{
+		{
+			name:   "without includes the action's own values win",
+			shared: sharedConfig,
+			strict: true,
+			action: `name: action
+description: action description
+author: action author
+inputs:
+  version:
+    description: action version
+runs:
+  using: node20
+  main: dist/index.js
+extend:
+  - from: "@/shared.yml"
+`,
+			want: `name: action
+description: action description
+author: action author
+inputs:
+    version:
+        description: action version
+        default: "1"
+    token:
+        description: shared token
+runs:
+    using: node20
+    main: dist/index.js
+branding:
+    color: purple
+`,
+		},
+		{
+			name:   "without includes in lenient mode",
+			shared: sharedConfig,
+			action: `name: action
+description: action description
+author: action author
+extend:
+  - from: "@/shared.yml"
+`,
+			want: `name: action
+description: action description
+author: action author
+inputs:
+    version:
+        description: shared version
+        default: "1"
+    token:
+        description: shared token
+runs:
+    using: composite
+    steps:
+        - run: echo setup
+          shell: bash
+branding:
+    color: purple
+`,
+		},
+		{
+			name:   "without includes runs of the same kind are merged",
+			strict: true,
+			shared: `runs:
+  using: node20
+  main: dist/index.js
+  post: dist/post.js
+`,
+			action: `name: action
+description: action description
+runs:
+  using: node20
+  main: dist/main.js
+extend:
+  - from: "@/shared.yml"
+`,
+			want: `name: action
+description: action description
+runs:
+    using: node20
+    main: dist/main.js
+    post: dist/post.js
+`,
+		},
+		{
+			name:   "included entries defined by the action conflict",
+			shared: sharedConfig,
+			strict: true,
+			action: `name: action
+description: action description
+inputs:
+  version:
+    description: action version
+runs:
+  using: node20
+  main: dist/index.js
+extend:
+  - from: "@/shared.yml"
+    include:
+      - field: inputs
+        include:
+          - version
+`,
+			err: "conflicting inputs.version",
+		},
+		{
+			name:   "the merge strategy deep merges the action over the extension",
+			shared: sharedConfig,
+			strict: true,
+			action: `name: action
+description: action description
+inputs:
+  version:
+    description: action version
+runs:
+  using: node20
+  main: dist/index.js
+extend:
+  - from: "@/shared.yml"
+    include:
+      - field: inputs
+        strategy: merge
+        include:
+          - version
+          - name: token
+            as: github-token
+`,
+			want: `name: action
+description: action description
+inputs:
+    version:
+        description: action version
+        default: "1"
+    github-token:
+        description: shared token
+runs:
+    using: node20
+    main: dist/index.js
+`,
+		},
+		{
+			name:   "the replace strategy swaps single inputs",
+			shared: sharedConfig,
+			strict: true,
+			action: `name: action
+description: action description
+inputs:
+  version:
+    description: action version
+    required: true
+  other:
+    description: other
+runs:
+  using: node20
+  main: dist/index.js
+extend:
+  - from: "@/shared.yml"
+    include:
+      - field: inputs
+        strategy: replace
+        include:
+          - version
+`,
+			want: `name: action
+description: action description
+inputs:
+    version:
+        description: shared version
+        default: "1"
+    other:
+        description: other
+runs:
+    using: node20
+    main: dist/index.js
+`,
+		},
+		{
+			name:   "the replace strategy swaps runs as a whole",
+			shared: sharedConfig,
+			strict: true,
+			action: `name: action
+description: action description
+runs:
+  using: node20
+  main: dist/index.js
+extend:
+  - from: "@/shared.yml"
+    include:
+      - field: runs
+        strategy: replace
+`,
+			want: `name: action
+description: action description
+runs:
+    using: composite
+    steps:
+        - run: echo setup
+          shell: bash
+`,
+		},
+		{
+			name:   "the replace strategy keeps the shared values of a bare extend",
+			shared: sharedConfig,
+			strict: true,
+			action: `name: action
+description: action description
+author: action author
+runs:
+  using: node20
+  main: dist/index.js
+extend:
+  - from: "@/shared.yml"
+    include:
+      - field: runs
+        strategy: replace
+      - field: author
+        strategy: replace
+`,
+			want: `name: action
+description: action description
+author: shared author
+runs:
+    using: composite
+    steps:
+        - run: echo setup
+          shell: bash
+`,
+		},
+		{
+			name:   "runs of different kinds conflict",
+			shared: sharedConfig,
+			strict: true,
+			action: `name: action
+description: action description
+runs:
+  using: node20
+  main: dist/index.js
+extend:
+  - from: "@/shared.yml"
+    include:
+      - field: runs
+`,
+			err: "conflicting runs",
+		},
+		{
+			name: "paths are merged into the action",
+			shared: `runs:
+  env:
+    A: a
+`,
+			strict: true,
+			action: `name: action
+description: action description
+runs:
+  using: docker
+  image: Dockerfile
+  env:
+    B: b
+extend:
+  - from: "@/shared.yml"
+    include:
+      - field: runs.env
+`,
+			want: `name: action
+description: action description
+runs:
+    using: docker
+    image: Dockerfile
+    env:
+        B: b
+        A: a
+`,
+		},
+		{
+			name:   "the prepend strategy adds shared steps before the action's own",
+			shared: sharedConfig,
+			strict: true,
+			action: `name: action
+description: action description
+runs:
+  using: composite
+  steps:
+    - run: make
+      shell: bash
+extend:
+  - from: "@/shared.yml"
+    include:
+      - field: runs.steps
+        strategy: prepend
+`,
+			want: `name: action
+description: action description
+runs:
+    using: composite
+    steps:
+        - run: echo setup
+          shell: bash
+        - run: make
+          shell: bash
+`,
+		},
+		{
+			name:   "the append strategy joins strings",
+			shared: sharedConfig,
+			strict: true,
+			action: `name: action
+description: action description
+runs:
+  using: node20
+  main: dist/index.js
+extend:
+  - from: "@/shared.yml"
+    include:
+      - field: description
+        strategy: append
+`,
+			want: `name: action
+description: |-
+    action description
+    shared description
+runs:
+    using: node20
+    main: dist/index.js
+`,
+		},
+		{
+			name:   "the append strategy does not combine runs of different kinds",
+			shared: sharedConfig,
+			strict: true,
+			action: `name: action
+description: action description
+runs:
+  using: node20
+  main: dist/index.js
+extend:
+  - from: "@/shared.yml"
+    include:
+      - field: runs
+        strategy: append
+`,
+			err: "conflicting runs.using",
+		},
+		{
+			name:   "unknown fields are errors in strict mode",
+			shared: sharedConfig,
+			strict: true,
+			action: `name: action
+description: action description
+runs:
+  using: node20
+  main: dist/index.js
+extend:
+  - from: "@/shared.yml"
+    include:
+      - field: input
+`,
+			err: "input does not exist",
+		},
+		{
+			name: "missing known fields are errors in lenient mode",
+			shared: `author: shared author
+`,
+			action: `name: action
+description: action description
+runs:
+  using: node20
+  main: dist/index.js
+extend:
+  - from: "@/shared.yml"
+    include:
+      - field: branding
+`,
+			err: "branding does not exist",
+		},
+		{
+			name:   "unknown fields are skipped in lenient mode",
+			shared: sharedConfig,
+			action: `name: action
+description: action description
+runs:
+  using: node20
+  main: dist/index.js
+extend:
+  - from: "@/shared.yml"
+    include:
+      - field: input
+      - field: author
+`,
+			want: `name: action
+author: shared author
+description: action description
+runs:
+    using: node20
+    main: dist/index.js
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			writeTestFile(t, path.Join(dir, "shared.yml"), test.shared)
+			writeTestFile(t, path.Join(dir, "action.yml"), test.action)
+
+			config, err := GetConfig(dir, path.Join(dir, "action.yml"), Options{Strict: test.strict})
+
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expected an error containing %q, got %v", test.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got, err := yaml.Marshal(config.mapping())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if string(got) != test.want {
+				t.Errorf("unexpected config:\n%s\nwant:\n%s", got, test.want)
+			}
+		})
+	}
+}
+
+func writeTestFile(t *testing.T, filename, contents string) {
+	t.Helper()
+
+	if err := os.WriteFile(filename, []byte(contents), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+type testPackages struct {
+	workspaces []*node.PackageInfo
+	reads      int
+}
+
+func (p *testPackages) ReadPackageInfo(filename string) (*node.PackageInfo, error) {
+	return &node.PackageInfo{Path: path.Dir(filename)}, nil
+}
+
+func (p *testPackages) GetWorkspaces(*node.PackageInfo) ([]*node.PackageInfo, error) {
+	p.reads++
+
+	return p.workspaces, nil
+}
+
+func (p *testPackages) GetDependencies(*node.PackageInfo) ([]*node.PackageInfo, error) {
+	return nil, nil
+}
+
+func TestActionExtends(t *testing.T) {
+	dir := t.TempDir()
+
+	for _, name := range []string{"a", "b", "c"} {
+		if err := os.Mkdir(path.Join(dir, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeTestFile(t, path.Join(dir, "a", "action.yml"), `name: a
+description: a description
+inputs:
+  version:
+    description: a version
+    default: "1"
+runs:
+  using: node20
+  main: dist/index.js
+`)
+	writeTestFile(t, path.Join(dir, "b", "action.yml"), `name: b
+description: b description
+inputs:
+  token:
+    description: b token
+runs:
+  using: node20
+  main: dist/index.js
+`)
+	writeTestFile(t, path.Join(dir, "c", "action.yml"), `name: c
+description: c description
+inputs:
+  version:
+    description: c version
+runs:
+  using: node20
+  main: dist/index.js
+extend:
+  - from: action:a
+    include:
+      - field: inputs
+  - from: action:b
+    include:
+      - field: inputs
+`)
+
+	packages := &testPackages{
+		workspaces: []*node.PackageInfo{
+			{Name: "a", Path: path.Join(dir, "a")},
+			{Name: "b", Path: path.Join(dir, "b")},
+			{Name: "c", Path: path.Join(dir, "c")},
+		},
+	}
+
+	config, err := GetConfig(dir, path.Join(dir, "c", "action.yml"), Options{Strict: true, Packages: packages})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := yaml.Marshal(config.mapping())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `name: c
+description: c description
+inputs:
+    version:
+        description: c version
+        default: "1"
+    token:
+        description: b token
+runs:
+    using: node20
+    main: dist/index.js
+`
+
+	if string(got) != want {
+		t.Errorf("unexpected config:\n%s\nwant:\n%s", got, want)
+	}
+
+	if packages.reads != 1 {
+		t.Errorf("expected the workspaces to be read once, read them %d times", packages.reads)
+	}
+}
+
+func TestPartialConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	writeTestFile(t, path.Join(dir, "shared.yml"), sharedConfig)
+	writeTestFile(t, path.Join(dir, "action.yml"), `name: action
+description: action description
+inputs:
+  version:
+    description: action version
+runs:
+  using: node20
+  main: dist/index.js
+extend:
+  - from: "@/shared.yml"
+    include:
+      - field: inputs
+      - field: branding
+      - field: input
+`)
+
+	config, err := GetConfig(dir, path.Join(dir, "action.yml"), Options{Strict: true, Partial: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		field    string
+		applied  bool
+		conflict bool
+	}{
+		{field: "inputs", conflict: true},
+		{field: "branding", applied: true},
+		{field: "input"},
+	}
+
+	if len(config.Extends()) != len(want) {
+		t.Fatalf("expected %d extends, got %d", len(want), len(config.Extends()))
+	}
+
+	for i, extend := range config.Extends() {
+		if extend.Field != want[i].field || extend.Applied != want[i].applied || extend.Conflict != want[i].conflict {
+			t.Errorf("unexpected extend %+v, want %+v", extend, want[i])
+		}
+	}
+
+	// a conflicting include is not applied at all, including its entries that do not conflict
+	if _, ok := (*config.Inputs)["token"]; ok {
+		t.Errorf("expected token not to be extended, as version conflicts")
+	}
+
+	if len(config.Overrides()) != 0 {
+		t.Errorf("expected no overrides, got %+v", config.Overrides())
+	}
+
+	if config.Branding == nil || config.Branding.Color == nil || *config.Branding.Color != "purple" {
+		t.Errorf("expected branding to be extended, got %+v", config.Branding)
+	}
+}
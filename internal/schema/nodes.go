@@ -0,0 +1,164 @@
+package schema
+
+import (
+	"gopkg.in/yaml.v3"
+)
+
+// fieldOrder is the order top-level fields are inserted in when an action does not define them itself.
+var fieldOrder = []string{
+	"name",
+	"author",
+	"description",
+	"inputs",
+	"outputs",
+	"runs",
+	"branding",
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func mappingKeys(node *yaml.Node) []string {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	var keys []string
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		keys = append(keys, node.Content[i].Value)
+	}
+
+	return keys
+}
+
+// setMappingValue replaces the value of key in place, or adds key to the end of the mapping.
+func setMappingValue(node *yaml.Node, key *yaml.Node, value *yaml.Node) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key.Value {
+			node.Content[i+1] = value
+
+			return
+		}
+	}
+
+	appendMappingValue(node, key, value)
+}
+
+// insertField adds a top-level field, or replaces its value in place. New fields are added before the
+// first field that comes after it in fieldOrder, and otherwise at the end.
+func insertField(node *yaml.Node, key *yaml.Node, value *yaml.Node) {
+	index := fieldIndex(key.Value)
+	if index == -1 || mappingKey(node, key.Value) != nil {
+		setMappingValue(node, key, value)
+
+		return
+	}
+
+	position := len(node.Content)
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if fieldIndex(node.Content[i].Value) > index {
+			position = i
+
+			break
+		}
+	}
+
+	content := make([]*yaml.Node, 0, len(node.Content)+2)
+	content = append(content, node.Content[:position]...)
+	content = append(content, key, value)
+	content = append(content, node.Content[position:]...)
+
+	node.Content = content
+}
+
+func appendMappingValue(node *yaml.Node, key *yaml.Node, value *yaml.Node) {
+	node.Content = append(node.Content, key, value)
+}
+
+func removeMappingKey(node *yaml.Node, key string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+
+			return
+		}
+	}
+}
+
+func mappingKey(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i]
+		}
+	}
+
+	return nil
+}
+
+func fieldIndex(key string) int {
+	for i, field := range fieldOrder {
+		if field == key {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func newKey(value string) *yaml.Node {
+	return &yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Tag:   "!!str",
+		Value: value,
+	}
+}
+
+func newMapping() *yaml.Node {
+	return &yaml.Node{
+		Kind: yaml.MappingNode,
+		Tag:  "!!map",
+	}
+}
+
+// cloneNode deep copies a node, so nodes from cached extension files are never modified. Aliases
+// are expanded, as their anchors may not be copied along with them. visit is called with every
+// original node and its copy.
+func cloneNode(node *yaml.Node, visit func(original, clone *yaml.Node)) *yaml.Node {
+	if node == nil {
+		return nil
+	}
+
+	if node.Kind == yaml.AliasNode {
+		return cloneNode(node.Alias, visit)
+	}
+
+	clone := *node
+	clone.Anchor = ""
+	clone.Content = nil
+
+	for _, child := range node.Content {
+		clone.Content = append(clone.Content, cloneNode(child, visit))
+	}
+
+	if visit != nil {
+		visit(node, &clone)
+	}
+
+	return &clone
+}
@@ -1,304 +1,358 @@
 package schema
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path"
+	"path/filepath"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 
 	"github.com/gravitational/gamma/internal/cache"
+	"github.com/gravitational/gamma/internal/node"
 )
 
 var configCache = cache.New[*Config]()
 
-func GetConfig(root, filename string) (*Config, error) {
-	var config CustomConfig
+type Options struct {
+	// Strict rejects unknown keys and extend fields, and reports every error when merging extensions
+	Strict bool
+	// Variables are the values of ${{ gamma.<name> }} in the resolved config
+	Variables map[string]string
+	// Packages resolves extends from other actions, written as action:<package-name>
+	Packages node.PackageService
+	// Partial records errors merging extensions as skipped extends instead of failing, so the config can
+	// still be inspected
+	Partial bool
+
+	actions *actionIndex
+}
+
+func GetConfig(root, filename string, options Options) (*Config, error) {
+	if options.actions == nil {
+		options.actions = newActionIndex(root, options.Packages)
+	}
+
+	config, err := getConfig(root, filename, options, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := interpolate(config, options.Variables); err != nil {
+		return nil, err
+	}
+
+	if err := config.mapping().Decode(config); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", filename, err)
+	}
+
+	if err := checkInputs(config); err != nil {
+		return nil, err
+	}
+
+	return config, nil
+}
+
+// ParseConfig parses an action.yml as it was published, which does not extend any other files.
+func ParseConfig(filename string, contents []byte) (*Config, error) {
+	var document yaml.Node
+
+	if err := yaml.Unmarshal(contents, &document); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", filename, err)
+	}
+
+	if document.Kind == 0 || document.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("error parsing %s: expected a mapping at the top level", filename)
+	}
+
+	return parseCustomConfig("", filename, &document, Options{}, []string{filename})
+}
+
+// getConfig resolves a file, where stack is the chain of files that extended it.
+func getConfig(root, filename string, options Options, stack []string) (*Config, error) {
+	var document yaml.Node
 
 	contents, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, fmt.Errorf("error reading %s: %v", filename, err)
 	}
 
-	if err := yaml.Unmarshal(contents, &config); err != nil {
+	if err := yaml.Unmarshal(contents, &document); err != nil {
 		return nil, fmt.Errorf("error parsing %s: %v", filename, err)
 	}
 
-	config.Path = filename
+	if document.Kind == 0 {
+		document = yaml.Node{
+			Kind:    yaml.DocumentNode,
+			Content: []*yaml.Node{newMapping()},
+		}
+	}
+
+	if document.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("error parsing %s: expected a mapping at the top level", filename)
+	}
+
+	if options.Strict {
+		if err := checkKnownFields(filename, contents, document.Content[0]); err != nil {
+			return nil, err
+		}
+	}
 
-	return parseCustomConfig(root, filename, config)
+	return parseCustomConfig(root, filename, &document, options, append(stack, filename))
 }
 
-func parseCustomConfig(root, filename string, customConfig CustomConfig) (*Config, error) {
+func parseCustomConfig(root, filename string, document *yaml.Node, options Options, stack []string) (*Config, error) {
+	var customConfig CustomConfig
+
+	if err := document.Decode(&customConfig); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", filename, err)
+	}
+
 	config := &Config{
-		Path:        customConfig.Path,
-		Name:        customConfig.Name,
-		Author:      customConfig.Author,
-		Description: customConfig.Description,
-		Inputs:      customConfig.Inputs,
-		Outputs:     customConfig.Outputs,
-		Runs:        customConfig.Runs,
-		Branding:    customConfig.Branding,
+		Path:    filename,
+		Node:    document,
+		sources: make(map[*yaml.Node]string),
+		strict:  options.Strict,
+		partial: options.Partial,
 	}
 
+	config.fragments = mappingValue(config.mapping(), "fragments")
+
+	removeMappingKey(config.mapping(), "extend")
+	removeMappingKey(config.mapping(), "fragments")
+
 	if customConfig.Extend != nil {
 		for _, extension := range *customConfig.Extend {
-			file := extension.From
-			if strings.HasPrefix(file, "@/") {
-				file = strings.TrimPrefix(file, "@/")
-				file = path.Join(root, file)
-			}
-			if !path.IsAbs(file) {
-				file = path.Join(filename, file)
+			from, fragment, _ := strings.Cut(extension.From, "#")
+
+			file, err := resolveExtension(root, filename, from, options.actions)
+			if err != nil {
+				return nil, err
 			}
 
 			var extensionConfig *Config
 			var ok bool
 
-			extensionConfig, ok = configCache.Get(file)
+			key := fmt.Sprintf("%s:%t:%t", file, options.Strict, options.Partial)
+
+			extensionConfig, ok = configCache.Get(key)
 			if !ok {
-				def, err := GetConfig(root, file)
+				for _, f := range stack {
+					if f == file {
+						return nil, &ExtendError{
+							Err:   errors.New("extend cycle detected"),
+							Root:  root,
+							Chain: append(append([]string{}, stack...), file),
+						}
+					}
+				}
+
+				def, err := getConfig(root, file, options, stack)
 				if err != nil {
-					return nil, err
+					var extendErr *ExtendError
+					if errors.As(err, &extendErr) {
+						return nil, err
+					}
+
+					return nil, &ExtendError{
+						Err:   err,
+						Root:  root,
+						Chain: append(append([]string{}, stack...), file),
+					}
 				}
 
-				configCache.Set(file, def)
+				configCache.Set(key, def)
 
 				extensionConfig = def
 			}
 
-			if err := mergeConfigs(config, extensionConfig, extension.Include); err != nil {
-				return nil, err
-			}
-		}
-	}
-
-	return config, nil
-}
+			if fragment != "" {
+				f, err := extensionConfig.fragment(fragment)
+				if err != nil {
+					return nil, err
+				}
 
-func mergeConfigs(base, extension *Config, includes *[]ExtensionInclude) error {
-	if includes != nil {
-		for _, include := range *includes {
-			field := include.Field
+				extensionConfig = f
+			}
 
-			switch field {
-			case "inputs":
-				if err := mergeInputs(base, extension, &include); err != nil {
-					return err
-				}
-			case "outputs":
-				if err := mergeOutputs(base, extension, &include); err != nil {
-					return err
-				}
-			case "branding":
-				if err := mergeBranding(base, extension, &include); err != nil {
-					return err
-				}
-			case "author":
-				if err := mergeAuthor(base, extension); err != nil {
-					return err
-				}
-			case "runs":
-				if err := mergeRuns(base, extension); err != nil {
-					return err
-				}
+			if err := mergeConfigs(config, extensionConfig, &extension); err != nil {
+				return nil, err
 			}
 		}
+	}
 
-		return nil
+	if err := config.mapping().Decode(config); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %v", filename, err)
 	}
 
-	_ = mergeInputs(base, extension, nil)
-	_ = mergeOutputs(base, extension, nil)
-	_ = mergeBranding(base, extension, nil)
-	_ = mergeAuthor(base, extension)
-	_ = mergeRuns(base, extension)
+	if err := checkSteps(config); err != nil {
+		return nil, err
+	}
 
-	return nil
+	return config, nil
 }
 
-func mergeInputs(base, extension *Config, includes *ExtensionInclude) error {
-	if extension.Inputs == nil {
-		return fmt.Errorf("no inputs exist in %s", extension.Path)
+// checkSteps makes sure the steps of a composite action, which may come from several files, have unique ids.
+func checkSteps(config *Config) error {
+	steps := mappingValue(mappingValue(config.mapping(), "runs"), "steps")
+	if steps == nil || steps.Kind != yaml.SequenceNode {
+		return nil
 	}
 
-	newInputs := make(InputMap)
+	ids := make(map[string]*yaml.Node)
 
-	if base.Inputs != nil {
-		for key, value := range *base.Inputs {
-			newInputs[key] = value
+	for _, step := range steps.Content {
+		id := mappingValue(step, "id")
+		if id == nil {
+			continue
 		}
+
+		if previous, ok := ids[id.Value]; ok {
+			return fmt.Errorf("duplicate step id %s, defined at %s and %s", id.Value, config.Position(previous), config.Position(id))
+		}
+
+		ids[id.Value] = id
 	}
 
-	if includes != nil && includes.Include != nil {
-		for _, field := range *includes.Include {
+	return nil
+}
 
-			inputs := *extension.Inputs
+// ExtendError is an error in a file that was extended, directly or indirectly, by another.
+type ExtendError struct {
+	Err   error
+	Root  string
+	Chain []string
+}
 
-			input, ok := inputs[field]
-			if !ok {
-				return fmt.Errorf("input %s does not exist in %s", field, extension.Path)
-			}
+func (e *ExtendError) Error() string {
+	return fmt.Sprintf("%v\n  extended via %s", e.Err, formatChain(e.Root, e.Chain))
+}
 
-			if _, ok := newInputs[field]; ok {
-				return fmt.Errorf("conflicting input, %s exists in both %s and %s", field, base.Path, extension.Path)
-			}
+func (e *ExtendError) Unwrap() error {
+	return e.Err
+}
 
-			newInputs[field] = input
+func formatChain(root string, chain []string) string {
+	var files []string
+	for _, file := range chain {
+		if rel, err := filepath.Rel(root, file); err == nil {
+			file = rel
 		}
-	} else {
-	outer:
-		for key, input := range *extension.Inputs {
-			if includes != nil && includes.Exclude != nil {
-				for _, exclude := range *includes.Exclude {
-					if exclude == key {
-						continue outer
-					}
-				}
-			}
 
-			newInputs[key] = input
-		}
+		files = append(files, file)
 	}
 
-	base.Inputs = &newInputs
-
-	return nil
+	return strings.Join(files, " -> ")
 }
 
-func mergeOutputs(base, extension *Config, includes *ExtensionInclude) error {
-	if extension.Outputs == nil {
-		return fmt.Errorf("no outputs exist in %s", extension.Path)
+func resolveExtension(root, filename, from string, actions *actionIndex) (string, error) {
+	if strings.HasPrefix(from, "action:") {
+		return actions.resolve(strings.TrimPrefix(from, "action:"))
 	}
 
-	newOutputs := make(OutputMap)
-
-	if base.Outputs != nil {
-		for key, value := range *base.Outputs {
-			newOutputs[key] = value
-		}
+	file := from
+	if strings.HasPrefix(file, "@/") {
+		file = strings.TrimPrefix(file, "@/")
+		file = path.Join(root, file)
+	}
+	if !path.IsAbs(file) {
+		file = path.Join(filename, file)
 	}
 
-	if includes != nil && includes.Include != nil {
-		for _, field := range *includes.Include {
+	return file, nil
+}
 
-			outputs := *extension.Outputs
+// actionIndex finds the action.yml of actions in the monorepo by their package name, reading the
+// workspaces once for every action extended while resolving a config.
+type actionIndex struct {
+	root     string
+	packages node.PackageService
+	files    map[string]string
+}
 
-			output, ok := outputs[field]
-			if !ok {
-				return fmt.Errorf("output %s does not exist in %s", field, extension.Path)
-			}
+func newActionIndex(root string, packages node.PackageService) *actionIndex {
+	return &actionIndex{root: root, packages: packages}
+}
 
-			if _, ok := newOutputs[field]; ok {
-				return fmt.Errorf("conflicting output, %s exists in both %s and %s", field, base.Path, extension.Path)
-			}
+// resolve returns the action.yml of the action with the given package name.
+func (i *actionIndex) resolve(name string) (string, error) {
+	if i.packages == nil {
+		return "", fmt.Errorf("can not extend from action %s, as actions can not be resolved here", name)
+	}
 
-			newOutputs[field] = output
+	if i.files == nil {
+		rootPackage, err := i.packages.ReadPackageInfo(path.Join(i.root, "package.json"))
+		if err != nil {
+			return "", err
 		}
-	} else {
-	outer:
-		for key, output := range *extension.Outputs {
-			if includes != nil && includes.Exclude != nil {
-				for _, exclude := range *includes.Exclude {
-					if exclude == key {
-						continue outer
-					}
-				}
-			}
 
-			newOutputs[key] = output
+		workspaces, err := i.packages.GetWorkspaces(rootPackage)
+		if err != nil {
+			return "", err
 		}
-	}
-
-	base.Outputs = &newOutputs
 
-	return nil
-}
+		i.files = make(map[string]string)
 
-func mergeBranding(base, extension *Config, includes *ExtensionInclude) error {
-	if extension.Branding == nil {
-		return fmt.Errorf("no branding exists in %s", extension.Path)
+		for _, ws := range workspaces {
+			i.files[ws.Name] = path.Join(ws.Path, "action.yml")
+		}
 	}
 
-	newBranding := &Branding{}
-	if base.Branding != nil {
-		newBranding = base.Branding
+	file, ok := i.files[name]
+	if !ok {
+		return "", fmt.Errorf("could not find an action named %s to extend from", name)
 	}
 
-	if includes != nil && includes.Include != nil {
-		for _, field := range *includes.Include {
-
-			switch field {
-			case "color":
-				newBranding.Color = extension.Branding.Color
-			case "icon":
-				newBranding.Icon = extension.Branding.Icon
-			}
-		}
-	} else {
-		var excludeColor bool
-		var excludeIcon bool
-
-		if includes != nil && includes.Exclude != nil {
-			for _, exclude := range *includes.Exclude {
-				switch exclude {
-				case "color":
-					excludeColor = true
-				case "icon":
-					excludeIcon = true
-				}
-			}
-		}
+	return file, nil
+}
 
-		if !excludeColor {
-			newBranding.Color = extension.Branding.Color
-		}
+// GetExtendGraph returns filename and every file it extends from, directly or indirectly.
+func GetExtendGraph(root, filename string, packages node.PackageService) ([]string, error) {
+	var files []string
 
-		if !excludeIcon {
-			newBranding.Icon = extension.Branding.Icon
-		}
-	}
+	actions := newActionIndex(root, packages)
+	seen := make(map[string]struct{})
+	queue := []string{filename}
 
-	base.Branding = newBranding
+	for len(queue) > 0 {
+		file := queue[0]
+		queue = queue[1:]
 
-	return nil
-}
+		if _, ok := seen[file]; ok {
+			continue
+		}
 
-func mergeRuns(base, extension *Config) error {
-	if extension.Runs.JavascriptRun == nil &&
-		extension.Runs.DockerRun == nil &&
-		extension.Runs.CompositeRun == nil {
-		return fmt.Errorf("runs is empty in %s", extension.Path)
-	}
+		seen[file] = struct{}{}
+		files = append(files, file)
 
-	if extension.Runs.JavascriptRun != nil {
-		base.Runs = Runs{
-			JavascriptRun: extension.Runs.JavascriptRun,
+		var config struct {
+			Extend *[]Extension `yaml:"extend"`
 		}
-	}
 
-	if extension.Runs.DockerRun != nil {
-		base.Runs = Runs{
-			DockerRun: extension.Runs.DockerRun,
+		contents, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("error reading %s: %v", file, err)
 		}
-	}
 
-	if extension.Runs.CompositeRun != nil {
-		base.Runs = Runs{
-			CompositeRun: extension.Runs.CompositeRun,
+		if err := yaml.Unmarshal(contents, &config); err != nil {
+			return nil, fmt.Errorf("error parsing %s: %v", file, err)
 		}
-	}
 
-	return nil
-}
+		if config.Extend != nil {
+			for _, extension := range *config.Extend {
+				from, _, _ := strings.Cut(extension.From, "#")
 
-func mergeAuthor(base, extension *Config) error {
-	if extension.Author == nil {
-		return fmt.Errorf("author is empty in %s", extension.Path)
-	}
+				extended, err := resolveExtension(root, file, from, actions)
+				if err != nil {
+					return nil, err
+				}
 
-	base.Author = extension.Author
+				queue = append(queue, extended)
+			}
+		}
+	}
 
-	return nil
+	return files, nil
 }
@@ -8,55 +8,232 @@ import (
 
 type Config struct {
 	Path        string     `yaml:"-"`
+	Node        *yaml.Node `yaml:"-"`
+	sources     map[*yaml.Node]string
+	strict      bool
+	partial     bool
+	fragments   *yaml.Node
+	extends     []*ExtendResult
+	overrides   []*Override
 	Name        string     `yaml:"name"`
 	Author      *string    `yaml:"author,omitempty"`
 	Description string     `yaml:"description"`
 	Inputs      *InputMap  `yaml:"inputs,omitempty"`
-	Outputs     *OutputMap `yaml:"output,omitempty"`
+	Outputs     *OutputMap `yaml:"outputs,omitempty"`
 	Runs        Runs       `yaml:"runs"`
 	Branding    *Branding  `yaml:"branding,omitempty"`
 }
 
+func (c *Config) mapping() *yaml.Node {
+	return c.Node.Content[0]
+}
+
+// importNode copies a node from an extension, remembering which file each part of it was defined in.
+func (c *Config) importNode(extension *Config, node *yaml.Node) *yaml.Node {
+	return cloneNode(node, func(original, clone *yaml.Node) {
+		c.sources[clone] = extension.Source(original)
+	})
+}
+
+// Source returns the file a node of the resolved config was defined in.
+func (c *Config) Source(node *yaml.Node) string {
+	if source, ok := c.sources[node]; ok {
+		return source
+	}
+
+	return c.Path
+}
+
+// Position returns the file and line a node of the resolved config was defined at.
+func (c *Config) Position(node *yaml.Node) string {
+	return fmt.Sprintf("%s:%d", c.Source(node), node.Line)
+}
+
+// InputNames returns the names of the config's inputs, in the order they are defined in.
+func (c *Config) InputNames() []string {
+	if c.Inputs == nil {
+		return nil
+	}
+
+	return mappingKeys(mappingValue(c.mapping(), "inputs"))
+}
+
+// OutputNames returns the names of the config's outputs, in the order they are defined in.
+func (c *Config) OutputNames() []string {
+	if c.Outputs == nil {
+		return nil
+	}
+
+	return mappingKeys(mappingValue(c.mapping(), "outputs"))
+}
+
+// InputLocation returns where an input of the config was defined.
+func (c *Config) InputLocation(name string) Location {
+	return c.Location(mappingKey(mappingValue(c.mapping(), "inputs"), name))
+}
+
+// OutputLocation returns where an output of the config was defined.
+func (c *Config) OutputLocation(name string) Location {
+	return c.Location(mappingKey(mappingValue(c.mapping(), "outputs"), name))
+}
+
+// Field returns the node of a top-level field of the config.
+func (c *Config) Field(name string) *yaml.Node {
+	return mappingValue(c.mapping(), name)
+}
+
 type CustomConfig struct {
 	Path        string       `yaml:"-"`
 	Name        string       `yaml:"name"`
 	Author      *string      `yaml:"author,omitempty"`
 	Description string       `yaml:"description"`
 	Inputs      *InputMap    `yaml:"inputs,omitempty"`
-	Outputs     *OutputMap   `yaml:"output,omitempty"`
+	Outputs     *OutputMap   `yaml:"outputs,omitempty"`
 	Runs        Runs         `yaml:"runs"`
 	Branding    *Branding    `yaml:"branding,omitempty"`
 	Extend      *[]Extension `yaml:"extend,omitempty"`
+	Fragments   *FragmentMap `yaml:"fragments,omitempty"`
+}
+
+// Fragment is a named part of an action.yml in a shared file, which actions can extend from by name.
+type Fragment struct {
+	Name        *string    `yaml:"name,omitempty"`
+	Author      *string    `yaml:"author,omitempty"`
+	Description *string    `yaml:"description,omitempty"`
+	Inputs      *InputMap  `yaml:"inputs,omitempty"`
+	Outputs     *OutputMap `yaml:"outputs,omitempty"`
+	Runs        *Runs      `yaml:"runs,omitempty"`
+	Branding    *Branding  `yaml:"branding,omitempty"`
 }
 
+type FragmentMap = map[string]Fragment
+
 type ExtensionInclude struct {
-	Field   string    `yaml:"field"`
-	Include *[]string `yaml:"include"`
-	Exclude *[]string `yaml:"exclude"`
+	Field    string         `yaml:"field" jsonschema:"required"`
+	Include  *[]IncludeItem `yaml:"include"`
+	Exclude  *[]string      `yaml:"exclude"`
+	Override *bool          `yaml:"override,omitempty"`
+	Strategy *Strategy      `yaml:"strategy,omitempty"`
+	// Before and After insert the included steps next to the step with this id, instead of merging them
+	Before *string `yaml:"before,omitempty"`
+	After  *string `yaml:"after,omitempty"`
+}
+
+// Strategy decides what happens when an included value is already defined.
+type Strategy string
+
+const (
+	// StrategyError fails with a conflict
+	StrategyError Strategy = "error"
+	// StrategyMerge deep merges the existing definition over the included one
+	StrategyMerge Strategy = "merge"
+	// StrategyReplace replaces the existing definition with the included one as a whole
+	StrategyReplace Strategy = "replace"
+	// StrategyAppend adds the included value after the existing one, joining lists, strings and mappings
+	StrategyAppend Strategy = "append"
+	// StrategyPrepend adds the included value before the existing one
+	StrategyPrepend Strategy = "prepend"
+)
+
+// strategy returns the include's strategy, where override: true is short for the merge strategy.
+func (i *ExtensionInclude) strategy(fallback Strategy) (Strategy, error) {
+	if i == nil {
+		return fallback, nil
+	}
+
+	if i.Strategy != nil {
+		switch *i.Strategy {
+		case StrategyError, StrategyMerge, StrategyReplace, StrategyAppend, StrategyPrepend:
+		default:
+			return "", fmt.Errorf("unsupported strategy %s for %s, expected error, merge, replace, append or prepend", *i.Strategy, i.Field)
+		}
+
+		if i.Override != nil && *i.Override && *i.Strategy != StrategyMerge {
+			return "", fmt.Errorf("override can not be combined with the %s strategy for %s", *i.Strategy, i.Field)
+		}
+
+		return *i.Strategy, nil
+	}
+
+	if i.Override != nil && *i.Override {
+		return StrategyMerge, nil
+	}
+
+	return fallback, nil
+}
+
+// IncludeItem is an included entry, written either as its name or as a mapping with a name and a
+// new name to include it as.
+type IncludeItem struct {
+	Name string `yaml:"name" jsonschema:"required"`
+	As   string `yaml:"as,omitempty"`
+}
+
+func (i *IncludeItem) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		i.Name = value.Value
+
+		return nil
+	}
+
+	var obj struct {
+		Name string `yaml:"name"`
+		As   string `yaml:"as"`
+	}
+	if err := value.Decode(&obj); err != nil {
+		return err
+	}
+
+	if obj.Name == "" {
+		return fmt.Errorf("line %d: included entries need a name", value.Line)
+	}
+
+	i.Name = obj.Name
+	i.As = obj.As
+
+	return nil
 }
 
 type Extension struct {
-	From    string              `yaml:"from"`
+	From    string              `yaml:"from" jsonschema:"required"`
 	Include *[]ExtensionInclude `yaml:"include"`
 }
 
 type Branding struct {
-	Color *string `json:"color"`
-	Icon  *string `json:"icon"`
+	Color *string `yaml:"color,omitempty"`
+	Icon  *string `yaml:"icon,omitempty"`
 }
 
 type Input struct {
 	Description        string  `yaml:"description"`
 	Required           *bool   `yaml:"required,omitempty"`
-	Default            *string `yaml:"default,omitempty"`
+	Default            *string `yaml:"default,omitempty" jsonschema:"scalar"`
 	DeprecationMessage *string `yaml:"deprecationMessage,omitempty"`
+	// Type, Choices and Pattern describe the values gamma accepts for the input, and are not published
+	Type    *InputType `yaml:"type,omitempty"`
+	Choices *[]string  `yaml:"choices,omitempty"`
+	Pattern *string    `yaml:"pattern,omitempty"`
 }
 
+// InputType is the kind of value an input accepts, which GitHub always passes as a string.
+type InputType string
+
+const (
+	InputTypeString  InputType = "string"
+	InputTypeBoolean InputType = "boolean"
+	InputTypeNumber  InputType = "number"
+	// InputTypeEnum accepts one of the input's choices
+	InputTypeEnum InputType = "enum"
+	// InputTypeList accepts values separated by new lines or commas
+	InputTypeList InputType = "list"
+)
+
 type InputMap = map[string]Input
 
 type Output struct {
 	Description string `yaml:"description"`
-	Value       string `yaml:"value"`
+	// Value is required for composite actions, and not used by Javascript or Docker actions
+	Value *string `yaml:"value,omitempty"`
 }
 
 type OutputMap = map[string]Output
@@ -74,15 +251,27 @@ type JavascriptRun struct {
 }
 
 type RunStep struct {
-	Run              *string  `yaml:"run,omitempty"`
-	Shell            *string  `yaml:"shell,omitempty"`
-	If               *string  `yaml:"if,omitempty"`
-	Name             *string  `yaml:"name,omitempty"`
-	ID               *string  `yaml:"id,omitempty"`
-	Env              *EnvMap  `yaml:"env,omitempty"`
-	WorkingDirectory *string  `yaml:"working-directory,omitempty"`
-	Uses             *string  `yaml:"uses,omitempty"`
-	With             *WithMap `yaml:"with,omitempty"`
+	Run              *string     `yaml:"run,omitempty"`
+	Shell            *string     `yaml:"shell,omitempty"`
+	If               *string     `yaml:"if,omitempty"`
+	Name             *string     `yaml:"name,omitempty"`
+	ID               *string     `yaml:"id,omitempty"`
+	Env              *EnvMap     `yaml:"env,omitempty"`
+	WorkingDirectory *string     `yaml:"working-directory,omitempty"`
+	Uses             *string     `yaml:"uses,omitempty"`
+	With             *WithMap    `yaml:"with,omitempty"`
+	ContinueOnError  *Expression `yaml:"continue-on-error,omitempty"`
+	TimeoutMinutes   *Expression `yaml:"timeout-minutes,omitempty"`
+}
+
+// Expression is a value that can be either a literal, such as true or 10, or an expression.
+type Expression string
+
+func (e Expression) MarshalYAML() (interface{}, error) {
+	return &yaml.Node{
+		Kind:  yaml.ScalarNode,
+		Value: string(e),
+	}, nil
 }
 
 type CompositeRun struct {
@@ -91,12 +280,36 @@ type CompositeRun struct {
 }
 
 type DockerRun struct {
-	PreEntrypoint  *string   `yaml:"pre-entrypoint,omitempty"`
-	Image          string    `yaml:"image"`
-	Env            *EnvMap   `yaml:"env,omitempty"`
-	Entrypoint     *string   `yaml:"entrypoint,omitempty"`
-	PostEntrypoint *string   `yaml:"post-entrypoint,omitempty"`
-	Args           *[]string `yaml:"args,omitempty"`
+	Using          string  `yaml:"using"`
+	PreEntrypoint  *string `yaml:"pre-entrypoint,omitempty"`
+	PreIf          *string `yaml:"pre-if,omitempty"`
+	Image          string  `yaml:"image"`
+	Env            *EnvMap `yaml:"env,omitempty"`
+	Entrypoint     *string `yaml:"entrypoint,omitempty"`
+	PostEntrypoint *string `yaml:"post-entrypoint,omitempty"`
+	PostIf         *string `yaml:"post-if,omitempty"`
+	Args           *Args   `yaml:"args,omitempty"`
+}
+
+// Args are the arguments passed to a Docker action's entrypoint, written either as a list or as a
+// single string.
+type Args []string
+
+func (a *Args) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		*a = Args{value.Value}
+
+		return nil
+	}
+
+	var args []string
+	if err := value.Decode(&args); err != nil {
+		return err
+	}
+
+	*a = args
+
+	return nil
 }
 
 type Runs struct {
@@ -134,6 +347,10 @@ func (r *Runs) UnmarshalYAML(value *yaml.Node) error {
 	r.Using = obj.Using
 
 	switch obj.Using {
+	// shared files can define parts of runs, such as steps, leaving using to the action
+	case "":
+		return nil
+
 	case "composite":
 		var compositeRun CompositeRun
 
@@ -168,5 +385,5 @@ func (r *Runs) UnmarshalYAML(value *yaml.Node) error {
 		return nil
 	}
 
-	return fmt.Errorf("unsupported runs.using value: %v, expected composite, docker, node12 or node16", obj.Using)
+	return fmt.Errorf("unsupported runs.using value: %v, expected composite, docker, node12, node16, node20 or node24", obj.Using)
 }
@@ -0,0 +1,131 @@
+package schema
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gravitational/gamma/internal/utils"
+)
+
+// extendableFields are the fields extended when an extend does not list what to include.
+var extendableFields = []string{"inputs", "outputs", "branding", "author", "runs"}
+
+// checkKnownFields reports every key in a file that is not part of gamma's action.yml format. yaml.v3
+// does not check the keys of types with their own unmarshaler, so runs and included items are checked
+// against the fields of their types separately.
+func checkKnownFields(filename string, contents []byte, root *yaml.Node) error {
+	var errs Errors
+
+	decoder := yaml.NewDecoder(bytes.NewReader(contents))
+	decoder.KnownFields(true)
+
+	var config CustomConfig
+	if err := decoder.Decode(&config); err != nil && !errors.Is(err, io.EOF) {
+		errs = errs.Append(fmt.Errorf("error parsing %s: %v", filename, err))
+	}
+
+	c := &fieldChecker{filename: filename, errs: errs}
+
+	c.checkRuns(mappingValue(root, "runs"), "runs")
+
+	if fragments := mappingValue(root, "fragments"); fragments != nil && fragments.Kind == yaml.MappingNode {
+		for i := 0; i+1 < len(fragments.Content); i += 2 {
+			c.checkRuns(mappingValue(fragments.Content[i+1], "runs"), fmt.Sprintf("fragments.%s.runs", fragments.Content[i].Value))
+		}
+	}
+
+	if extend := mappingValue(root, "extend"); extend != nil && extend.Kind == yaml.SequenceNode {
+		for i, extension := range extend.Content {
+			include := mappingValue(extension, "include")
+			if include == nil || include.Kind != yaml.SequenceNode {
+				continue
+			}
+
+			for j, item := range include.Content {
+				if items := mappingValue(item, "include"); items != nil && items.Kind == yaml.SequenceNode {
+					for k, entry := range items.Content {
+						c.check(entry, fmt.Sprintf("extend[%d].include[%d].include[%d]", i, j, k), yamlFields(IncludeItem{}))
+					}
+				}
+			}
+		}
+	}
+
+	return c.errs.Err()
+}
+
+type fieldChecker struct {
+	filename string
+	errs     Errors
+}
+
+func (c *fieldChecker) check(node *yaml.Node, name string, allowed []string) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+
+		if !utils.Contains(allowed, key.Value) {
+			c.errs = c.errs.Append(&ValidationError{
+				File:    c.filename,
+				Line:    key.Line,
+				Message: fmt.Sprintf("unknown key %s in %s, expected one of %s", key.Value, name, strings.Join(allowed, ", ")),
+			})
+		}
+	}
+}
+
+// checkRuns checks runs against the fields of the kind of runs its using decides. Shared files can
+// leave using out, in which case the fields of every kind are allowed.
+func (c *fieldChecker) checkRuns(runs *yaml.Node, name string) {
+	if runs == nil {
+		return
+	}
+
+	allowed := append(append(yamlFields(JavascriptRun{}), yamlFields(DockerRun{})...), yamlFields(CompositeRun{})...)
+
+	if using := mappingValue(runs, "using"); using != nil {
+		switch using.Value {
+		case "docker":
+			allowed = yamlFields(DockerRun{})
+		case "composite":
+			allowed = yamlFields(CompositeRun{})
+		default:
+			allowed = yamlFields(JavascriptRun{})
+		}
+	}
+
+	c.check(runs, name, allowed)
+
+	if steps := mappingValue(runs, "steps"); steps != nil && steps.Kind == yaml.SequenceNode {
+		for i, step := range steps.Content {
+			c.check(step, fmt.Sprintf("%s.steps[%d]", name, i), yamlFields(RunStep{}))
+		}
+	}
+}
+
+// yamlFields returns the yaml names of a struct's fields.
+func yamlFields(v interface{}) []string {
+	t := reflect.TypeOf(v)
+
+	var fields []string
+
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("yaml"), ",")
+		if !t.Field(i).IsExported() || name == "" || name == "-" || utils.Contains(fields, name) {
+			continue
+		}
+
+		fields = append(fields, name)
+	}
+
+	return fields
+}
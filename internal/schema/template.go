@@ -0,0 +1,76 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// templateExpression matches ${{ gamma.<name> }}, leaving GitHub's own expressions such as
+// ${{ inputs.version }} alone.
+var templateExpression = regexp.MustCompile(`\$\{\{\s*gamma\.([^\s}]*)\s*\}\}`)
+
+// interpolate replaces every gamma template variable in the values of a resolved config.
+func interpolate(config *Config, variables map[string]string) error {
+	var errs Errors
+
+	var walk func(node *yaml.Node)
+	walk = func(node *yaml.Node) {
+		if node.Kind == yaml.MappingNode {
+			for i := 0; i+1 < len(node.Content); i += 2 {
+				walk(node.Content[i+1])
+			}
+
+			return
+		}
+
+		if node.Kind == yaml.SequenceNode {
+			for _, child := range node.Content {
+				walk(child)
+			}
+
+			return
+		}
+
+		if node.Kind != yaml.ScalarNode || !strings.Contains(node.Value, "gamma.") {
+			return
+		}
+
+		node.Value = templateExpression.ReplaceAllStringFunc(node.Value, func(match string) string {
+			name := templateExpression.FindStringSubmatch(match)[1]
+
+			value, ok := variables[name]
+			if !ok {
+				errs = errs.Append(fmt.Errorf("%s: unknown variable gamma.%s, expected one of %s", config.Position(node), name, variableNames(variables)))
+
+				return match
+			}
+
+			if value == "" {
+				errs = errs.Append(fmt.Errorf("%s: gamma.%s has no value", config.Position(node), name))
+
+				return match
+			}
+
+			return value
+		})
+	}
+
+	walk(config.mapping())
+
+	return errs.Err()
+}
+
+func variableNames(variables map[string]string) string {
+	var names []string
+	for name := range variables {
+		names = append(names, "gamma."+name)
+	}
+
+	sort.Strings(names)
+
+	return strings.Join(names, ", ")
+}
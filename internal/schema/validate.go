@@ -0,0 +1,281 @@
+package schema
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/gravitational/gamma/internal/utils"
+)
+
+var identifier = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
+
+var (
+	inputFields           = yamlFields(Input{})
+	outputFields          = yamlFields(Output{})
+	brandingFields        = yamlFields(Branding{})
+	javascriptRunFields   = yamlFields(JavascriptRun{})
+	dockerRunFields       = yamlFields(DockerRun{})
+	compositeRunFields    = yamlFields(CompositeRun{})
+	compositeStepFields   = yamlFields(RunStep{})
+	javascriptRunRequired = []string{"main"}
+	dockerRunRequired     = []string{"image"}
+	compositeRunRequired  = []string{"steps"}
+)
+
+type ValidationError struct {
+	File    string
+	Line    int
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Message)
+}
+
+type validator struct {
+	config *Config
+	errors []*ValidationError
+}
+
+// Validate checks a resolved config against GitHub's metadata rules for action.yml.
+func Validate(config *Config) []*ValidationError {
+	v := &validator{config: config}
+
+	root := config.mapping()
+
+	v.requireScalar(root, "name")
+	v.requireScalar(root, "description")
+
+	if author := mappingValue(root, "author"); author != nil && author.Kind != yaml.ScalarNode {
+		v.errorf(author, "author should be a string")
+	}
+
+	if inputs := mappingValue(root, "inputs"); inputs != nil {
+		v.validateEntries(inputs, "input", inputFields)
+
+		for i := 0; i+1 < len(inputs.Content); i += 2 {
+			input := inputs.Content[i+1]
+
+			if required := mappingValue(input, "required"); required != nil && required.ShortTag() != "!!bool" {
+				v.errorf(required, "required for input %s should be true or false", inputs.Content[i].Value)
+			}
+		}
+	}
+
+	if outputs := mappingValue(root, "outputs"); outputs != nil {
+		v.validateEntries(outputs, "output", outputFields)
+		v.validateOutputValues(outputs)
+	}
+
+	if runs := mappingValue(root, "runs"); runs == nil {
+		v.errorf(root, "runs is required")
+	} else {
+		v.validateRuns(runs)
+	}
+
+	if branding := mappingValue(root, "branding"); branding != nil {
+		v.validateBranding(branding)
+	}
+
+	return v.errors
+}
+
+func (v *validator) errorf(node *yaml.Node, format string, a ...any) {
+	v.errors = append(v.errors, &ValidationError{
+		File:    v.config.Source(node),
+		Line:    node.Line,
+		Message: fmt.Sprintf(format, a...),
+	})
+}
+
+func (v *validator) requireScalar(node *yaml.Node, field string) {
+	value := mappingValue(node, field)
+	if value == nil {
+		v.errorf(node, "%s is required", field)
+
+		return
+	}
+
+	if value.Kind != yaml.ScalarNode || value.Value == "" {
+		v.errorf(value, "%s should be a non-empty string", field)
+	}
+}
+
+func (v *validator) requireMapping(node *yaml.Node, name string) bool {
+	if node.Kind != yaml.MappingNode {
+		v.errorf(node, "%s should be a mapping", name)
+
+		return false
+	}
+
+	return true
+}
+
+func (v *validator) allowFields(node *yaml.Node, name string, allowed []string) {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key := node.Content[i]
+
+		if !utils.Contains(allowed, key.Value) {
+			v.errorf(key, "%s is not allowed in %s, expected one of %s", key.Value, name, strings.Join(allowed, ", "))
+		}
+	}
+}
+
+// validateEntries validates a mapping of inputs or outputs, which need valid IDs and a description.
+func (v *validator) validateEntries(node *yaml.Node, kind string, allowed []string) {
+	if !v.requireMapping(node, kind+"s") {
+		return
+	}
+
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		name := fmt.Sprintf("%s %s", kind, key.Value)
+
+		if !identifier.MatchString(key.Value) {
+			v.errorf(key, "%s is not a valid ID, it should start with a letter or _ and only contain alphanumeric characters, - or _", name)
+		}
+
+		if !v.requireMapping(value, name) {
+			continue
+		}
+
+		v.allowFields(value, name, allowed)
+		v.requireScalar(value, "description")
+	}
+}
+
+// validateOutputValues checks that every output of a composite action has a value, once every extend is merged.
+func (v *validator) validateOutputValues(outputs *yaml.Node) {
+	using := mappingValue(mappingValue(v.config.mapping(), "runs"), "using")
+	if using == nil || using.Value != "composite" || outputs.Kind != yaml.MappingNode {
+		return
+	}
+
+	for i := 0; i+1 < len(outputs.Content); i += 2 {
+		if mappingValue(outputs.Content[i+1], "value") == nil {
+			v.errorf(outputs.Content[i], "output %s needs a value, as composite actions require one", outputs.Content[i].Value)
+		}
+	}
+}
+
+func (v *validator) validateRuns(runs *yaml.Node) {
+	if !v.requireMapping(runs, "runs") {
+		return
+	}
+
+	using := mappingValue(runs, "using")
+	if using == nil {
+		v.errorf(runs, "runs.using is required")
+
+		return
+	}
+
+	var allowed, required []string
+
+	switch using.Value {
+	case "node12", "node16", "node20", "node24":
+		allowed, required = javascriptRunFields, javascriptRunRequired
+	case "docker":
+		allowed, required = dockerRunFields, dockerRunRequired
+	case "composite":
+		allowed, required = compositeRunFields, compositeRunRequired
+	default:
+		v.errorf(using, "unsupported runs.using value: %s, expected composite, docker, node12, node16, node20 or node24", using.Value)
+
+		return
+	}
+
+	name := fmt.Sprintf("runs for %s actions", using.Value)
+
+	v.allowFields(runs, name, allowed)
+
+	for _, field := range required {
+		if mappingValue(runs, field) == nil {
+			v.errorf(runs, "runs.%s is required for %s actions", field, using.Value)
+		}
+	}
+
+	for _, field := range []string{"pre", "post"} {
+		if condition := mappingKey(runs, field+"-if"); condition != nil && mappingValue(runs, field) == nil && mappingValue(runs, field+"-entrypoint") == nil {
+			v.errorf(condition, "runs.%s-if is set without runs.%s", field, field)
+		}
+	}
+
+	if steps := mappingValue(runs, "steps"); steps != nil && using.Value == "composite" {
+		v.validateSteps(steps)
+	}
+}
+
+func (v *validator) validateSteps(steps *yaml.Node) {
+	if steps.Kind != yaml.SequenceNode {
+		v.errorf(steps, "runs.steps should be a list")
+
+		return
+	}
+
+	ids := make(map[string]int)
+
+	for i, step := range steps.Content {
+		name := fmt.Sprintf("step %d", i+1)
+
+		if !v.requireMapping(step, name) {
+			continue
+		}
+
+		v.allowFields(step, name, compositeStepFields)
+
+		run, uses := mappingValue(step, "run"), mappingValue(step, "uses")
+
+		switch {
+		case run == nil && uses == nil:
+			v.errorf(step, "%s needs either run or uses", name)
+		case run != nil && uses != nil:
+			v.errorf(step, "%s can not have both run and uses", name)
+		case run != nil:
+			if mappingValue(step, "shell") == nil {
+				v.errorf(step, "%s needs a shell, as it uses run", name)
+			}
+
+			if with := mappingKey(step, "with"); with != nil {
+				v.errorf(with, "with is only allowed in %s if it uses an action", name)
+			}
+		case uses != nil:
+			for _, field := range []string{"shell", "working-directory"} {
+				if key := mappingKey(step, field); key != nil {
+					v.errorf(key, "%s is only allowed in %s if it uses run", field, name)
+				}
+			}
+		}
+
+		if id := mappingValue(step, "id"); id != nil {
+			if !identifier.MatchString(id.Value) {
+				v.errorf(id, "%s has an invalid id %s, it should start with a letter or _ and only contain alphanumeric characters, - or _", name, id.Value)
+			}
+
+			if previous, ok := ids[id.Value]; ok {
+				v.errorf(id, "%s has the same id %s as step %d", name, id.Value, previous)
+			}
+
+			ids[id.Value] = i + 1
+		}
+	}
+}
+
+func (v *validator) validateBranding(branding *yaml.Node) {
+	if !v.requireMapping(branding, "branding") {
+		return
+	}
+
+	v.allowFields(branding, "branding", brandingFields)
+
+	if color := mappingValue(branding, "color"); color != nil && !utils.Contains(brandingColors, color.Value) {
+		v.errorf(color, "branding color %s is not supported, expected one of %s", color.Value, strings.Join(brandingColors, ", "))
+	}
+
+	if icon := mappingValue(branding, "icon"); icon != nil && !utils.Contains(brandingIcons, icon.Value) {
+		v.errorf(icon, "branding icon %s is not a supported Feather icon", icon.Value)
+	}
+}
@@ -0,0 +1,53 @@
+package schema
+
+import (
+	"path"
+	"testing"
+)
+
+func TestValidateCompositeOutputs(t *testing.T) {
+	dir := t.TempDir()
+
+	writeTestFile(t, path.Join(dir, "shared.yml"), `outputs:
+  path:
+    description: The path
+`)
+	writeTestFile(t, path.Join(dir, "action.yml"), `name: action
+description: action description
+inputs:
+  version: {}
+outputs:
+  url:
+    description: The url
+    value: ${{ steps.upload.outputs.url }}
+runs:
+  using: composite
+  steps:
+    - run: echo
+      shell: bash
+extend:
+  - from: "@/shared.yml"
+`)
+
+	config, err := GetConfig(dir, path.Join(dir, "action.yml"), Options{Strict: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []ValidationError{
+		{File: path.Join(dir, "action.yml"), Line: 4, Message: "description is required"},
+		{File: path.Join(dir, "shared.yml"), Line: 2, Message: "output path needs a value, as composite actions require one"},
+	}
+
+	errs := Validate(config)
+
+	if len(errs) != len(want) {
+		t.Fatalf("expected %d errors, got %v", len(want), errs)
+	}
+
+	for i, err := range errs {
+		if *err != want[i] {
+			t.Errorf("got %v, want %v", err, &want[i])
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package size
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+
+	"github.com/gravitational/gamma/internal/config"
+	"github.com/gravitational/gamma/internal/logger"
+)
+
+// Sizes maps each file in an action's output, relative to the output directory, to its size in bytes.
+type Sizes map[string]int64
+
+// Baseline maps action names to the sizes of their output files.
+type Baseline map[string]Sizes
+
+type Delta struct {
+	File     string
+	Previous int64
+	Current  int64
+	Existed  bool
+	Exists   bool
+}
+
+func (d Delta) Change() int64 {
+	return d.Current - d.Previous
+}
+
+func Measure(directory string) (Sizes, error) {
+	sizes := make(Sizes)
+
+	err := filepath.WalkDir(directory, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(directory, p)
+		if err != nil {
+			return fmt.Errorf("could not resolve relative path between %s and %s: %v", directory, p, err)
+		}
+
+		sizes[filepath.ToSlash(rel)] = info.Size()
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not measure %s: %v", directory, err)
+	}
+
+	return sizes, nil
+}
+
+func (s Sizes) Total() int64 {
+	var total int64
+	for _, size := range s {
+		total += size
+	}
+
+	return total
+}
+
+func ReadBaseline(filename string) (Baseline, error) {
+	contents, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("could not read size baseline %s: %v", filename, err)
+	}
+
+	var baseline Baseline
+	if err := json.Unmarshal(contents, &baseline); err != nil {
+		return nil, fmt.Errorf("could not parse size baseline %s: %v", filename, err)
+	}
+
+	return baseline, nil
+}
+
+func (b Baseline) Write(filename string) error {
+	contents, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(filename, append(contents, '\n'), 0644); err != nil {
+		return fmt.Errorf("could not write size baseline %s: %v", filename, err)
+	}
+
+	return nil
+}
+
+// Compare returns a delta for every file that exists in either previous or current, sorted by filename.
+// A nil previous means there is nothing to compare against.
+func Compare(previous, current Sizes) []Delta {
+	files := make(map[string]struct{})
+	for file := range previous {
+		files[file] = struct{}{}
+	}
+	for file := range current {
+		files[file] = struct{}{}
+	}
+
+	var deltas []Delta
+	for file := range files {
+		p, existed := previous[file]
+		c, exists := current[file]
+
+		deltas = append(deltas, Delta{
+			File:     file,
+			Previous: p,
+			Current:  c,
+			Existed:  existed,
+			Exists:   exists,
+		})
+	}
+
+	sort.Slice(deltas, func(i, j int) bool {
+		return deltas[i].File < deltas[j].File
+	})
+
+	return deltas
+}
+
+func Table(name string, previous, current Sizes) string {
+	t := table.NewWriter()
+	t.SetTitle(name)
+	t.AppendHeader(table.Row{"File", "Previous", "Current", "Delta"})
+
+	for _, delta := range Compare(previous, current) {
+		t.AppendRow(table.Row{
+			delta.File,
+			formatSize(delta.Previous, delta.Existed),
+			formatSize(delta.Current, delta.Exists),
+			formatChange(delta.Change(), previous != nil),
+		})
+	}
+
+	t.AppendFooter(table.Row{
+		"Total",
+		formatSize(previous.Total(), previous != nil),
+		config.FormatBytes(current.Total()),
+		formatChange(current.Total()-previous.Total(), previous != nil),
+	})
+
+	t.SetColumnConfigs([]table.ColumnConfig{
+		{Number: 2, Align: text.AlignRight},
+		{Number: 3, Align: text.AlignRight},
+		{Number: 4, Align: text.AlignRight},
+	})
+
+	return t.Render()
+}
+
+// Check measures the output of an action, logs how it compares with the previous sizes and checks it
+// against the budget. The measured sizes are returned even when the budget is exceeded.
+func Check(name, directory string, budget *config.SizeBudget, previous Sizes) (Sizes, error) {
+	current, err := Measure(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("output sizes of action " + name + "\n" + Table(name, previous, current))
+
+	return current, CheckBudget(budget, previous, current)
+}
+
+// CheckBudget returns an error listing every way current exceeds the budget.
+func CheckBudget(budget *config.SizeBudget, previous, current Sizes) error {
+	if budget == nil {
+		return nil
+	}
+
+	var violations []string
+
+	if budget.Total != nil && current.Total() > int64(*budget.Total) {
+		violations = append(violations, fmt.Sprintf("total size %s exceeds the budget of %s", config.FormatBytes(current.Total()), budget.Total))
+	}
+
+	if budget.Increase != nil && previous != nil {
+		increase := current.Total() - previous.Total()
+		if increase > int64(*budget.Increase) {
+			violations = append(violations, fmt.Sprintf("total size grew by %s, more than the allowed %s", config.FormatBytes(increase), budget.Increase))
+		}
+	}
+
+	var patterns []string
+	for pattern := range budget.Files {
+		patterns = append(patterns, pattern)
+	}
+
+	sort.Strings(patterns)
+
+	var files []string
+	for file := range current {
+		files = append(files, file)
+	}
+
+	sort.Strings(files)
+
+	for _, pattern := range patterns {
+		max := budget.Files[pattern]
+
+		for _, file := range files {
+			matched, err := path.Match(pattern, file)
+			if err != nil {
+				return fmt.Errorf("invalid size budget pattern %q: %v", pattern, err)
+			}
+
+			if matched && current[file] > int64(max) {
+				violations = append(violations, fmt.Sprintf("%s is %s, exceeding the budget of %s for %s", file, config.FormatBytes(current[file]), max, pattern))
+			}
+		}
+	}
+
+	if len(violations) > 0 {
+		return errors.New("size budget exceeded: " + strings.Join(violations, ", "))
+	}
+
+	return nil
+}
+
+func formatSize(n int64, ok bool) string {
+	if !ok {
+		return "-"
+	}
+
+	return config.FormatBytes(n)
+}
+
+func formatChange(n int64, ok bool) string {
+	if !ok {
+		return "-"
+	}
+
+	if n > 0 {
+		return "+" + config.FormatBytes(n)
+	}
+
+	return config.FormatBytes(n)
+}
@@ -0,0 +1,95 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// MoveDirectory renames src to dst, falling back to a recursive copy when they live on different devices.
+func MoveDirectory(src, dst string) error {
+	err := os.Rename(src, dst)
+	if err == nil {
+		return nil
+	}
+
+	if !errors.Is(err, syscall.EXDEV) {
+		return err
+	}
+
+	if err := CopyDirectory(src, dst); err != nil {
+		return err
+	}
+
+	if err := os.RemoveAll(src); err != nil {
+		return fmt.Errorf("could not remove %s: %v", src, err)
+	}
+
+	return nil
+}
+
+// CopyDirectory recursively copies src to dst, preserving file modes and symlinks.
+func CopyDirectory(src, dst string) error {
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return fmt.Errorf("could not resolve relative path between %s and %s: %v", src, p, err)
+		}
+
+		target := filepath.Join(dst, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case d.IsDir():
+			if err := os.MkdirAll(target, info.Mode().Perm()); err != nil {
+				return fmt.Errorf("could not create directory %s: %v", target, err)
+			}
+
+			return nil
+
+		case info.Mode()&fs.ModeSymlink != 0:
+			link, err := os.Readlink(p)
+			if err != nil {
+				return fmt.Errorf("could not read link %s: %v", p, err)
+			}
+
+			return os.Symlink(link, target)
+		}
+
+		return CopyFile(p, target, info.Mode().Perm())
+	})
+}
+
+func CopyFile(src, dst string, perm fs.FileMode) error {
+	source, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+
+	defer source.Close()
+
+	destination, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return fmt.Errorf("could not create file: %v", err)
+	}
+
+	if _, err := io.Copy(destination, source); err != nil {
+		destination.Close()
+
+		return err
+	}
+
+	return destination.Close()
+}
@@ -26,3 +26,14 @@ func NormalizeDirectories(workingDirectory, outputDirectory string) (string, str
 
 	return workingDirectory, outputDirectory, nil
 }
+
+// Contains returns whether values includes value.
+func Contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
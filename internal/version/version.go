@@ -0,0 +1,145 @@
+package version
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version, such as 1.2.3 or 2.0.0-beta.1.
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	Prerelease string
+}
+
+func Parse(value string) (*Version, error) {
+	v := strings.TrimPrefix(value, "v")
+
+	v, _, _ = strings.Cut(v, "+")
+	v, prerelease, _ := strings.Cut(v, "-")
+
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("invalid version %s, expected major.minor.patch", value)
+	}
+
+	var numbers [3]int
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid version %s, expected major.minor.patch", value)
+		}
+
+		numbers[i] = n
+	}
+
+	return &Version{numbers[0], numbers[1], numbers[2], prerelease}, nil
+}
+
+func (v *Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Prerelease != "" {
+		s += "-" + v.Prerelease
+	}
+
+	return s
+}
+
+// Compare returns -1, 0 or 1 as a is lower than, equal to or higher than b. A prerelease is lower than
+// its release, and prereleases of the same version are compared as strings.
+func Compare(a, b *Version) int {
+	for _, parts := range [][2]int{{a.Major, b.Major}, {a.Minor, b.Minor}, {a.Patch, b.Patch}} {
+		if parts[0] != parts[1] {
+			if parts[0] < parts[1] {
+				return -1
+			}
+
+			return 1
+		}
+	}
+
+	switch {
+	case a.Prerelease == b.Prerelease:
+		return 0
+	case a.Prerelease == "":
+		return 1
+	case b.Prerelease == "":
+		return -1
+	}
+
+	return strings.Compare(a.Prerelease, b.Prerelease)
+}
+
+// IsMajorBump returns whether current can contain breaking changes since previous. Before 1.0.0, a
+// minor bump is enough, as is usual for semantic versions, and a prerelease can break anything until
+// the next version of its major, such as 2.0.0-beta.1 and 2.0.0.
+func IsMajorBump(previous, current *Version) bool {
+	if previous.Prerelease != "" && current.Major == previous.Major && (previous.Major != 0 || current.Minor == previous.Minor) {
+		return Compare(current, previous) > 0
+	}
+
+	if previous.Major == 0 {
+		return current.Major > 0 || current.Minor > previous.Minor
+	}
+
+	return current.Major > previous.Major
+}
+
+// Bump is the part of a version a release increments.
+type Bump string
+
+const (
+	Major Bump = "major"
+	Minor Bump = "minor"
+	Patch Bump = "patch"
+)
+
+func ParseBump(value string) (Bump, error) {
+	switch b := Bump(value); b {
+	case Major, Minor, Patch:
+		return b, nil
+	}
+
+	return "", fmt.Errorf("invalid bump %s, expected major, minor or patch", value)
+}
+
+// Greater returns whichever of two bumps increments the larger part of a version.
+func Greater(a, b Bump) Bump {
+	if a == Major || b == Major {
+		return Major
+	}
+
+	if a == Minor || b == Minor {
+		return Minor
+	}
+
+	return Patch
+}
+
+// Bump returns the next version for a bump. A prerelease is released as is when the bump would not
+// go past it, so 2.0.0-beta.1 becomes 2.0.0 with a major bump.
+func (v *Version) Bump(b Bump) *Version {
+	next := &Version{Major: v.Major, Minor: v.Minor, Patch: v.Patch}
+
+	switch b {
+	case Major:
+		if v.Prerelease == "" || v.Minor != 0 || v.Patch != 0 {
+			next.Major++
+			next.Minor = 0
+			next.Patch = 0
+		}
+	case Minor:
+		if v.Prerelease == "" || v.Patch != 0 {
+			next.Minor++
+			next.Patch = 0
+		}
+	case Patch:
+		if v.Prerelease == "" {
+			next.Patch++
+		}
+	}
+
+	return next
+}
@@ -0,0 +1,95 @@
+package version
+
+import "testing"
+
+func mustParse(t *testing.T, value string) *Version {
+	t.Helper()
+
+	v, err := Parse(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return v
+}
+
+func TestIsMajorBump(t *testing.T) {
+	tests := []struct {
+		previous string
+		current  string
+		want     bool
+	}{
+		{"1.2.3", "2.0.0", true},
+		{"1.2.3", "1.3.0", false},
+		{"1.2.3", "1.2.3", false},
+		{"1.2.3", "2.0.0-beta.1", true},
+		{"0.2.3", "0.3.0", true},
+		{"0.2.3", "0.2.4", false},
+		{"0.2.3", "1.0.0", true},
+		{"2.0.0", "1.9.9", false},
+		{"2.0.0-beta.1", "2.0.0", true},
+		{"2.0.0-beta.1", "2.0.0-beta.2", true},
+		{"2.0.0-beta.1", "2.1.0", true},
+		{"2.0.0-beta.2", "2.0.0-beta.1", false},
+		{"2.0.0-beta.1", "3.0.0", true},
+		{"0.3.0-beta.1", "0.3.0", true},
+		{"0.3.0-beta.1", "0.3.1", true},
+		{"0.3.0-beta.1", "0.4.0", true},
+		{"0.3.0-beta.1", "0.2.0", false},
+	}
+
+	for _, test := range tests {
+		if got := IsMajorBump(mustParse(t, test.previous), mustParse(t, test.current)); got != test.want {
+			t.Errorf("IsMajorBump(%s, %s) = %t, want %t", test.previous, test.current, got, test.want)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{"1.2.3", "1.2.3", 0},
+		{"v1.2.3", "1.2.3", 0},
+		{"1.2.3", "1.10.0", -1},
+		{"2.0.0", "1.10.0", 1},
+		{"1.2.4", "1.2.3", 1},
+		{"2.0.0-beta.1", "2.0.0", -1},
+		{"2.0.0-beta.2", "2.0.0-beta.1", 1},
+		{"2.0.0-beta.1", "1.9.9", 1},
+	}
+
+	for _, test := range tests {
+		if got := Compare(mustParse(t, test.a), mustParse(t, test.b)); got != test.want {
+			t.Errorf("Compare(%s, %s) = %d, want %d", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestBump(t *testing.T) {
+	tests := []struct {
+		version string
+		bump    Bump
+		want    string
+	}{
+		{"1.2.3", Major, "2.0.0"},
+		{"1.2.3", Minor, "1.3.0"},
+		{"1.2.3", Patch, "1.2.4"},
+		{"0.2.3", Major, "1.0.0"},
+		{"2.0.0-beta.1", Major, "2.0.0"},
+		{"2.0.0-beta.1", Minor, "2.0.0"},
+		{"2.0.0-beta.1", Patch, "2.0.0"},
+		{"2.1.0-beta.1", Major, "3.0.0"},
+		{"2.1.0-beta.1", Minor, "2.1.0"},
+		{"2.1.1-beta.1", Minor, "2.2.0"},
+		{"2.1.1-beta.1", Patch, "2.1.1"},
+	}
+
+	for _, test := range tests {
+		if got := mustParse(t, test.version).Bump(test.bump).String(); got != test.want {
+			t.Errorf("%s with a %s bump = %s, want %s", test.version, test.bump, got, test.want)
+		}
+	}
+}
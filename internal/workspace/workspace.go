@@ -4,6 +4,7 @@ import (
 	"path"
 
 	"github.com/gravitational/gamma/internal/action"
+	"github.com/gravitational/gamma/internal/config"
 	"github.com/gravitational/gamma/internal/node"
 )
 
@@ -14,13 +15,24 @@ type Workspace interface {
 type workspace struct {
 	workingDirectory string
 	outputDirectory  string
+	options          Options
 	packages         node.PackageService
 }
 
-func New(workingDirectory, outputDirectory string) Workspace {
+type Options struct {
+	// KeepDist copies each action's dist into the output directory instead of moving it
+	KeepDist bool
+	// Header adds a comment to each action.yml naming the file and commit it was generated from
+	Header bool
+	// Commit is the monorepo commit being built
+	Commit string
+}
+
+func New(workingDirectory, outputDirectory string, options Options) Workspace {
 	return &workspace{
 		workingDirectory,
 		outputDirectory,
+		options,
 		node.NewPackageService(workingDirectory),
 	}
 }
@@ -45,6 +57,11 @@ func (w *workspace) CollectActions() ([]action.Action, error) {
 			WorkingDirectory: w.workingDirectory,
 			OutputDirectory:  outputDirectory,
 			PackageInfo:      ws,
+			KeepDist:         w.options.KeepDist,
+			Header:           w.options.Header,
+			Commit:           w.options.Commit,
+			Gamma:            config.Merge(rootPackage.Gamma, ws.Gamma),
+			Packages:         w.packages,
 		}
 
 		action, err := action.New(config)
@@ -1,5 +1,7 @@
 package main
 
+//go:generate go run . schema -o action.schema.json
+
 import (
 	"os"
 